@@ -0,0 +1,68 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Kinds of the resources that Notifications can send their messages through
+	IntegrationKind        = "Integration"
+	ClusterIntegrationKind = "ClusterIntegration"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=clusterintegrations,scope=Cluster
+// +kubebuilder:subresource:status
+
+// ClusterIntegration is the Schema for the clusterintegrations API.
+// It is an Integration shared with the Notifications of all the namespaces, even when tenancy is enabled
+type ClusterIntegration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IntegrationSpec   `json:"spec,omitempty"`
+	Status IntegrationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterIntegrationList contains a list of ClusterIntegration.
+type ClusterIntegrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterIntegration `json:"items"`
+}
+
+// ToIntegration return a copy of the ClusterIntegration as an Integration of kind ClusterIntegration,
+// so it is handled by everything built for Integrations
+func (c *ClusterIntegration) ToIntegration() *Integration {
+	return &Integration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: GroupVersion.String(),
+			Kind:       ClusterIntegrationKind,
+		},
+		ObjectMeta: *c.ObjectMeta.DeepCopy(),
+		Spec:       *c.Spec.DeepCopy(),
+		Status:     *c.Status.DeepCopy(),
+	}
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterIntegration{}, &ClusterIntegrationList{})
+}
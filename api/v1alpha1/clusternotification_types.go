@@ -0,0 +1,73 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Kinds of the resources defining the Notifications
+	NotificationKind        = "Notification"
+	ClusterNotificationKind = "ClusterNotification"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,categories={notifications}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description=""
+// +kubebuilder:printcolumn:name="Triggers",type="integer",JSONPath=".status.triggerCount",description=""
+// +kubebuilder:printcolumn:name="Failures",type="integer",JSONPath=".status.failureCount",description=""
+// +kubebuilder:printcolumn:name="Last Triggered",type="date",JSONPath=".status.lastTriggeredTime",description=""
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description=""
+
+// ClusterNotification is the Schema for the clusternotifications API.
+// It is a Notification defined for the whole cluster, which is never restricted by tenancy
+type ClusterNotification struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NotificationSpec   `json:"spec,omitempty"`
+	Status NotificationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterNotificationList contains a list of ClusterNotification.
+type ClusterNotificationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterNotification `json:"items"`
+}
+
+// ToNotification return a copy of the ClusterNotification as a Notification of kind ClusterNotification.
+// It has no namespace, so it never collides with the namespaced ones in the registries
+func (c *ClusterNotification) ToNotification() *Notification {
+	return &Notification{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: GroupVersion.String(),
+			Kind:       ClusterNotificationKind,
+		},
+		ObjectMeta: *c.ObjectMeta.DeepCopy(),
+		Spec:       *c.Spec.DeepCopy(),
+		Status:     *c.Status.DeepCopy(),
+	}
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterNotification{}, &ClusterNotificationList{})
+}
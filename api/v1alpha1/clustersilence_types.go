@@ -0,0 +1,70 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ClusterSilenceKind is the kind of the Silences defined for the whole cluster
+	ClusterSilenceKind = "ClusterSilence"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,categories={notifications}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Active",type="boolean",JSONPath=".status.active",description=""
+// +kubebuilder:printcolumn:name="Silenced",type="integer",JSONPath=".status.silencedCount",description=""
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description=""
+
+// ClusterSilence is the Schema for the clustersilences API.
+// It is a Silence matching the Notifications of all the namespaces, and the ClusterNotifications
+type ClusterSilence struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SilenceSpec   `json:"spec,omitempty"`
+	Status SilenceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterSilenceList contains a list of ClusterSilence.
+type ClusterSilenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterSilence `json:"items"`
+}
+
+// ToSilence return a copy of the ClusterSilence as a Silence of kind ClusterSilence.
+// It has no namespace, so it never collides with the namespaced ones in the registry
+func (c *ClusterSilence) ToSilence() *Silence {
+	return &Silence{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: GroupVersion.String(),
+			Kind:       ClusterSilenceKind,
+		},
+		ObjectMeta: *c.ObjectMeta.DeepCopy(),
+		Spec:       *c.Spec.DeepCopy(),
+		Status:     *c.Status.DeepCopy(),
+	}
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterSilence{}, &ClusterSilenceList{})
+}
@@ -31,6 +31,64 @@ type IntegrationWebhook struct {
 	Verb      string            `json:"verb"`
 	Headers   map[string]string `json:"headers,omitempty"`
 	Validator string            `json:"validator,omitempty"`
+
+	// Timeout is the maximum time to wait for the receiver to reply. Defaults to 10s
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// SuccessStatusCodes is the list of status codes considered a successful delivery. Defaults to any 2xx
+	// +kubebuilder:validation:items:Minimum=100
+	// +kubebuilder:validation:items:Maximum=599
+	SuccessStatusCodes []int `json:"successStatusCodes,omitempty"`
+}
+
+// IntegrationSlack defines how messages are sent to Slack.
+// They are posted to the incoming webhook when 'webhookUrl' is set, or through chat.postMessage API when 'token' is set
+type IntegrationSlack struct {
+	// WebhookUrl is the URL of a Slack incoming webhook
+	WebhookUrl string `json:"webhookUrl,omitempty"`
+
+	// Token is the bot token used to call Slack API.
+	// It is recommended to expand it from the credentials. Example: ${SLACK_TOKEN}
+	Token string `json:"token,omitempty"`
+
+	// Channel is the channel where messages are posted when Notifications do not override it
+	Channel string `json:"channel,omitempty"`
+
+	// Validator checks the structure of outgoing messages before sending them
+	Validator string `json:"validator,omitempty"`
+
+	// Timeout is the maximum time to wait for Slack to reply. Defaults to 10s
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// IntegrationAlertmanager defines how alerts are sent to Alertmanager
+type IntegrationAlertmanager struct {
+	// Url is the base URL of Alertmanager. Alerts are posted to its '/api/v2/alerts' endpoint
+	Url     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Timeout is the maximum time to wait for Alertmanager to reply. Defaults to 10s
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// IntegrationDelivery defines how failed deliveries are retried.
+// Messages are queued per Integration and delivered in order
+type IntegrationDelivery struct {
+	// MaxAttempts is the maximum number of attempts to deliver a message, including the first one. Defaults to 3
+	// +kubebuilder:validation:Minimum=1
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+
+	// InitialBackoff is the time to wait before the first retry. It is doubled on each retry. Defaults to 1s
+	InitialBackoff *metav1.Duration `json:"initialBackoff,omitempty"`
+
+	// MaxBackoff is the maximum time to wait between retries. Defaults to 1m
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+
+	// QueueSize is the maximum number of messages waiting to be delivered.
+	// Messages coming when the queue is full are discarded. Changes are applied with the next message,
+	// once the messages waiting fit in the new size. Defaults to 1000
+	// +kubebuilder:validation:Minimum=1
+	QueueSize int `json:"queueSize,omitempty"`
 }
 
 // IntegrationSpec defines the desired state of Integration.
@@ -39,6 +97,15 @@ type IntegrationSpec struct {
 
 	Type    string             `json:"type"`
 	Webhook IntegrationWebhook `json:"webhook,omitempty"`
+	Slack   IntegrationSlack   `json:"slack,omitempty"`
+
+	Alertmanager IntegrationAlertmanager `json:"alertmanager,omitempty"`
+
+	Delivery IntegrationDelivery `json:"delivery,omitempty"`
+
+	// SharedNamespaces is the list of namespaces whose Notifications can use this Integration when tenancy is enabled.
+	// Use '*' to share it with all of them. It is ignored when tenancy is disabled
+	SharedNamespaces []string `json:"sharedNamespaces,omitempty"`
 }
 
 // IntegrationStatus defines the observed state of Integration.
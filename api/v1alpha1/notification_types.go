@@ -21,46 +21,236 @@ import (
 )
 
 // TODO
+// +kubebuilder:validation:XValidation:rule="!has(self.namespace) || !has(self.namespaceSelector)",message="namespace and namespaceSelector are mutually exclusive"
 type NotificationExtraResource struct {
 	Group     string `json:"group"`
 	Version   string `json:"version"`
 	Resource  string `json:"resource"`
 	Namespace string `json:"namespace,omitempty"`
 	Name      string `json:"name,omitempty"`
+
+	// LabelSelector filters the resources by their labels. Example: 'app.kubernetes.io/name=example'
+	LabelSelector string `json:"labelSelector,omitempty"`
+
+	// FieldSelector filters the resources by their fields. Example: 'status.phase=Running'
+	FieldSelector string `json:"fieldSelector,omitempty"`
+
+	// NamespaceSelector selects the namespaces of the resources by their labels. Example: 'team=payments'.
+	// Namespaces are tracked, so resources are watched as namespaces appear or change their labels
+	NamespaceSelector string `json:"namespaceSelector,omitempty"`
 }
 
 // TODO
+// +kubebuilder:validation:XValidation:rule="!has(self.namespace) || !has(self.namespaceSelector)",message="namespace and namespaceSelector are mutually exclusive"
 type NotificationWatch struct {
 	Group     string `json:"group"`
 	Version   string `json:"version"`
 	Resource  string `json:"resource"`
 	Namespace string `json:"namespace,omitempty"`
 	Name      string `json:"name,omitempty"`
+
+	// LabelSelector filters the watched resources by their labels. Example: 'app.kubernetes.io/name=example'
+	LabelSelector string `json:"labelSelector,omitempty"`
+
+	// FieldSelector filters the watched resources by their fields. Example: 'status.phase=Running'
+	FieldSelector string `json:"fieldSelector,omitempty"`
+
+	// NamespaceSelector selects the namespaces of the resources by their labels. Example: 'team=payments'.
+	// Namespaces are tracked, so resources are watched as namespaces appear or change their labels
+	NamespaceSelector string `json:"namespaceSelector,omitempty"`
+
+	// EventTypes are the types of the events evaluated. Defaults to all of them
+	EventTypes []NotificationEventType `json:"eventTypes,omitempty"`
+
+	// IgnoreInitialList skips the ADDED events produced for existing objects when the watcher starts
+	IgnoreInitialList bool `json:"ignoreInitialList,omitempty"`
+
+	// IgnoreResyncs skips the MODIFIED events produced by periodic resyncs, where the object did not change
+	IgnoreResyncs bool `json:"ignoreResyncs,omitempty"`
 }
 
-// TODO
+// NotificationTriggerMode defines when messages are sent for objects meeting the conditions
+// +kubebuilder:validation:Enum=Always;OnTransition
+type NotificationTriggerMode string
+
+const (
+	NotificationTriggerModeAlways       NotificationTriggerMode = "Always"
+	NotificationTriggerModeOnTransition NotificationTriggerMode = "OnTransition"
+)
+
+// NotificationEventType is the type of an event coming from watched resources
+// +kubebuilder:validation:Enum=ADDED;MODIFIED;DELETED
+type NotificationEventType string
+
+const (
+	NotificationEventTypeAdded    NotificationEventType = "ADDED"
+	NotificationEventTypeModified NotificationEventType = "MODIFIED"
+	NotificationEventTypeDeleted  NotificationEventType = "DELETED"
+)
+
+// NotificationConditionOperator is the comparison performed between the rendered key and the values of a condition
+// +kubebuilder:validation:Enum=Equal;NotEqual;In;NotIn;Matches;GreaterThan;LessThan;Exists
+type NotificationConditionOperator string
+
+const (
+	NotificationConditionOperatorEqual       NotificationConditionOperator = "Equal"
+	NotificationConditionOperatorNotEqual    NotificationConditionOperator = "NotEqual"
+	NotificationConditionOperatorIn          NotificationConditionOperator = "In"
+	NotificationConditionOperatorNotIn       NotificationConditionOperator = "NotIn"
+	NotificationConditionOperatorMatches     NotificationConditionOperator = "Matches"
+	NotificationConditionOperatorGreaterThan NotificationConditionOperator = "GreaterThan"
+	NotificationConditionOperatorLessThan    NotificationConditionOperator = "LessThan"
+	NotificationConditionOperatorExists      NotificationConditionOperator = "Exists"
+)
+
+// NotificationCondition compares the result of rendering 'key' with 'value' or 'values',
+// or evaluates a CEL 'expression' when it is set
 type NotificationCondition struct {
-	Name  string `json:"name"`
-	Key   string `json:"key"`
-	Value string `json:"value"`
+	Name string `json:"name"`
+	Key  string `json:"key,omitempty"`
+
+	// Expression is a CEL expression returning a bool, evaluated instead of 'key'.
+	// It can use the variables 'object', 'previousObject', 'eventType' and 'sources'
+	Expression string `json:"expression,omitempty"`
+
+	// Operator is the comparison performed. Defaults to Equal
+	Operator NotificationConditionOperator `json:"operator,omitempty"`
+
+	// Value is compared with the rendered key by Equal, NotEqual, Matches (regular expression),
+	// GreaterThan and LessThan (numbers or quantities)
+	Value string `json:"value,omitempty"`
+
+	// Values are compared with the rendered key by In and NotIn
+	Values []string `json:"values,omitempty"`
 }
 
+// NotificationConditionGroup is met when all its conditions are met
+type NotificationConditionGroup struct {
+	Name       string                  `json:"name,omitempty"`
+	Conditions []NotificationCondition `json:"conditions"`
+}
+
+// NotificationIntegration references the Integration messages are sent through
 type NotificationIntegration struct {
+	// Kind of the referenced resource. ClusterIntegrations are usable from every namespace
+	// +kubebuilder:validation:Enum=Integration;ClusterIntegration
+	// +kubebuilder:default=Integration
+	Kind string `json:"kind,omitempty"`
+
 	Name string `json:"name"`
 }
 
-// TODO
+// NotificationSlack defines the options for messages sent through Slack integrations
+type NotificationSlack struct {
+	// Channel overrides the channel configured in the Integration
+	Channel string `json:"channel,omitempty"`
+
+	// ThreadByObject posts the messages triggered by the same object as replies in one thread.
+	// It requires the Integration to use a token
+	ThreadByObject bool `json:"threadByObject,omitempty"`
+}
+
+// NotificationAlertmanager defines how alerts are built for Alertmanager integrations.
+// Values of labels, annotations and generatorUrl admit Go templating
+type NotificationAlertmanager struct {
+	// Labels of the alert. Label 'alertname' defaults to the name of the Notification
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations of the alert. Annotation 'description' defaults to the rendered 'data'
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	GeneratorUrl string `json:"generatorUrl,omitempty"`
+}
+
+// NotificationThrottling defines how many messages are sent for objects meeting the conditions.
+// Messages suppressed since the last one sent with the same fingerprint are counted in '.suppressed' for templates
+type NotificationThrottling struct {
+	// Fingerprint identifies the messages considered duplicated. It admits Go templating.
+	// Defaults to the namespace and name of the object
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// DeduplicationWindow is the time during which only one message is sent per fingerprint
+	DeduplicationWindow *metav1.Duration `json:"deduplicationWindow,omitempty"`
+
+	// MaxMessages is the maximum number of messages sent by the Notification per interval. Unlimited when not set
+	// +kubebuilder:validation:Minimum=1
+	MaxMessages int `json:"maxMessages,omitempty"`
+
+	// Interval is the time in which 'maxMessages' can be sent. Defaults to 1m
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Burst is the maximum number of messages sent at once. Defaults to 'maxMessages'
+	// +kubebuilder:validation:Minimum=1
+	Burst int `json:"burst,omitempty"`
+}
+
+// NotificationGrouping defines how objects meeting the conditions are batched into digests.
+// When it is set, 'data' of messages is rendered once per digest, with the batched objects in '.objects'
+type NotificationGrouping struct {
+	// GroupBy identifies the digest where the object is batched. It admits Go templating.
+	// All the objects are batched together when it is not set
+	GroupBy string `json:"groupBy,omitempty"`
+
+	// GroupWait is the time to wait for more objects before sending the first digest of a group. Defaults to 30s
+	GroupWait *metav1.Duration `json:"groupWait,omitempty"`
+
+	// GroupInterval is the time to wait before sending the next digest of a group. Defaults to 5m
+	GroupInterval *metav1.Duration `json:"groupInterval,omitempty"`
+}
+
+// NotificationMessage defines a message sent through an Integration when the conditions are met
 type NotificationMessage struct {
 	Integration NotificationIntegration `json:"integration"`
 	Data        string                  `json:"data"`
+
+	Slack        NotificationSlack        `json:"slack,omitempty"`
+	Alertmanager NotificationAlertmanager `json:"alertmanager,omitempty"`
 }
 
 // NotificationSpec defines the desired state of Notification
+// +kubebuilder:validation:XValidation:rule="has(self.message) != has(self.messages)",message="exactly one of message or messages must be set"
 type NotificationSpec struct {
 	Watch          NotificationWatch           `json:"watch"`
 	ExtraResources []NotificationExtraResource `json:"extraResources,omitempty"`
-	Conditions     []NotificationCondition     `json:"conditions"`
-	Message        NotificationMessage         `json:"message"`
+
+	// Message is sent through one Integration. Use 'messages' to send them through several ones
+	Message *NotificationMessage `json:"message,omitempty"`
+
+	// Messages are sent through their own Integration, all of them rendered from one evaluation of the conditions
+	// +kubebuilder:validation:MinItems=1
+	Messages []NotificationMessage `json:"messages,omitempty"`
+
+	// Conditions must be met all of them
+	Conditions []NotificationCondition `json:"conditions,omitempty"`
+
+	// AnyOf requires, when defined, that at least one of the groups is met too
+	AnyOf []NotificationConditionGroup `json:"anyOf,omitempty"`
+
+	// TriggerMode defines when messages are sent for objects meeting the conditions.
+	// Always sends them on every event, and OnTransition only when the object starts meeting them. Defaults to Always
+	TriggerMode NotificationTriggerMode `json:"triggerMode,omitempty"`
+
+	// Throttling limits the messages sent for objects meeting the conditions
+	Throttling NotificationThrottling `json:"throttling,omitempty"`
+
+	// Grouping batches the objects meeting the conditions into digests, sent instead of one message per object
+	Grouping *NotificationGrouping `json:"grouping,omitempty"`
+}
+
+// GetMessages return the messages of the Notification, whether they are defined in 'message' or 'messages'
+func (s *NotificationSpec) GetMessages() []NotificationMessage {
+	if s.Message != nil {
+		return append([]NotificationMessage{*s.Message}, s.Messages...)
+	}
+	return s.Messages
+}
+
+// NotificationObjectReference identifies a watched object
+type NotificationObjectReference struct {
+	ApiVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
 }
 
 // NotificationStatus defines the observed state of Notification
@@ -68,11 +258,31 @@ type NotificationStatus struct {
 
 	// Conditions represent the latest available observations of an object's state
 	Conditions []metav1.Condition `json:"conditions"`
+
+	// LastTriggeredTime is the last time an object met the conditions
+	LastTriggeredTime *metav1.Time `json:"lastTriggeredTime,omitempty"`
+
+	// LastTriggeredObject is the last object that met the conditions
+	LastTriggeredObject *NotificationObjectReference `json:"lastTriggeredObject,omitempty"`
+
+	// TriggerCount is the number of times objects met the conditions
+	TriggerCount int64 `json:"triggerCount,omitempty"`
+
+	// FailureCount is the number of times templates could not be rendered or messages could not be delivered
+	FailureCount int64 `json:"failureCount,omitempty"`
+
+	// LastError is the last error rendering templates or delivering messages
+	LastError     string       `json:"lastError,omitempty"`
+	LastErrorTime *metav1.Time `json:"lastErrorTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Namespaced,categories={notifications}
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description=""
+// +kubebuilder:printcolumn:name="Triggers",type="integer",JSONPath=".status.triggerCount",description=""
+// +kubebuilder:printcolumn:name="Failures",type="integer",JSONPath=".status.failureCount",description=""
+// +kubebuilder:printcolumn:name="Last Triggered",type="date",JSONPath=".status.lastTriggeredTime",description=""
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description=""
 
 // Notification is the Schema for the notifications API.
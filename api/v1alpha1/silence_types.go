@@ -0,0 +1,114 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SilenceNotificationMatcher selects the Notifications silenced. They must be in the namespace of the Silence,
+// unless it is a ClusterSilence, which also selects ClusterNotifications
+type SilenceNotificationMatcher struct {
+	// Name of the Notification. All the Notifications are matched when it is not set
+	Name string `json:"name,omitempty"`
+
+	// LabelSelector filters the Notifications by their labels. Example: 'team=payments,tier!=critical'
+	LabelSelector string `json:"labelSelector,omitempty"`
+}
+
+// SilenceObjectMatcher selects the watched objects whose messages are silenced
+type SilenceObjectMatcher struct {
+	// Namespace of the object. Objects of all the namespaces are matched when it is not set
+	Namespace string `json:"namespace,omitempty"`
+
+	// LabelSelector filters the objects by their labels. Example: 'app=payments'
+	LabelSelector string `json:"labelSelector,omitempty"`
+}
+
+// SilenceSpec defines the desired state of Silence.
+// Messages are silenced always, between 'startsAt' and 'endsAt' when any of them is set,
+// or during 'duration' after each time matching 'schedule' when it is set
+// +kubebuilder:validation:XValidation:rule="!has(self.schedule) || has(self.duration)",message="duration is required when schedule is set"
+type SilenceSpec struct {
+	Notification SilenceNotificationMatcher `json:"notification,omitempty"`
+	Object       SilenceObjectMatcher       `json:"object,omitempty"`
+
+	// StartsAt is the time from which messages are silenced
+	StartsAt *metav1.Time `json:"startsAt,omitempty"`
+
+	// EndsAt is the time until which messages are silenced
+	EndsAt *metav1.Time `json:"endsAt,omitempty"`
+
+	// Schedule is a cron expression with the times the silence starts, in 'timeZone'.
+	// Example: '0 2 * * 6' (Saturdays at 2AM)
+	Schedule string `json:"schedule,omitempty"`
+
+	// TimeZone is the IANA name of the time zone of 'schedule', like 'Europe/Madrid'. Defaults to UTC.
+	// Times skipped when clocks move forward never start the silence, and times repeated when they move back start it twice
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Duration is the time the silence lasts every time it starts on schedule
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Comment explains why messages are silenced
+	Comment string `json:"comment,omitempty"`
+}
+
+// SilenceStatus defines the observed state of Silence.
+type SilenceStatus struct {
+
+	// Conditions represent the latest available observations of an object's state
+	Conditions []metav1.Condition `json:"conditions"`
+
+	// Active reports whether messages are being silenced now
+	Active bool `json:"active,omitempty"`
+
+	// SilencedCount is the number of messages silenced
+	SilencedCount int64 `json:"silencedCount,omitempty"`
+
+	// LastSilencedTime is the last time a message was silenced
+	LastSilencedTime *metav1.Time `json:"lastSilencedTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,categories={notifications}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Active",type="boolean",JSONPath=".status.active",description=""
+// +kubebuilder:printcolumn:name="Silenced",type="integer",JSONPath=".status.silencedCount",description=""
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description=""
+
+// Silence is the Schema for the silences API.
+type Silence struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SilenceSpec   `json:"spec,omitempty"`
+	Status SilenceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SilenceList contains a list of Silence.
+type SilenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Silence `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Silence{}, &SilenceList{})
+}
@@ -35,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -43,13 +44,22 @@ import (
 	notifikv1alpha1 "freepik.com/notifik/api/v1alpha1"
 	"freepik.com/notifik/internal/controller/integrations"
 	"freepik.com/notifik/internal/controller/notifications"
+	"freepik.com/notifik/internal/controller/silences"
 	"freepik.com/notifik/internal/controller/sources"
 	"freepik.com/notifik/internal/controller/watchers"
+	"freepik.com/notifik/internal/deadletter"
+	"freepik.com/notifik/internal/delivery"
+	"freepik.com/notifik/internal/events"
 	"freepik.com/notifik/internal/globals"
+	"freepik.com/notifik/internal/informers"
+	"freepik.com/notifik/internal/metrics"
 	integrationsRegistry "freepik.com/notifik/internal/registry/integrations"
 	notificationsRegistry "freepik.com/notifik/internal/registry/notifications"
+	silencesRegistry "freepik.com/notifik/internal/registry/silences"
 	sourcesRegistry "freepik.com/notifik/internal/registry/sources"
 	watchersRegistry "freepik.com/notifik/internal/registry/watchers"
+	"freepik.com/notifik/internal/tracker"
+	webhookv1alpha1 "freepik.com/notifik/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -77,6 +87,13 @@ func main() {
 	var tlsOpts []func(*tls.Config)
 
 	var informerDurationToResync time.Duration
+	var integrationHealthCheckPeriod time.Duration
+	var deadLetterNamespace string
+	var deadLetterMaxEntries int
+	var recordObjectEvents bool
+	var notificationStatusFlushPeriod time.Duration
+	var enableWebhooks bool
+	var enableTenancy bool
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -98,6 +115,13 @@ func main() {
 
 	//
 	flag.DurationVar(&informerDurationToResync, "informer-duration-to-resync", 300*time.Second, "Duration to wait until resyncing all the objects by informers")
+	flag.DurationVar(&integrationHealthCheckPeriod, "integration-health-check-period", 300*time.Second, "Duration to wait between health checks of the backends behind Integrations. Use 0 to check them only on changes")
+	flag.StringVar(&deadLetterNamespace, "dead-letter-namespace", "", "Namespace where the messages that could not be delivered are stored in ConfigMaps. Leave it empty to discard them")
+	flag.IntVar(&deadLetterMaxEntries, "dead-letter-max-entries", 100, "Maximum number of messages stored per Integration. The oldest ones are discarded first")
+	flag.BoolVar(&recordObjectEvents, "record-object-events", false, "Record Kubernetes events on the watched objects too, not only on Notifications")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false, "If set, admission webhooks validating Notifications and Integrations of both scopes are served. They require certificates (see --webhook-cert-path)")
+	flag.BoolVar(&enableTenancy, "enable-tenancy", false, "If set, Notifications can only watch and source objects in their namespace or those allowed by the annotations of their Namespace, and only use Integrations shared with their namespace")
+	flag.DurationVar(&notificationStatusFlushPeriod, "notification-status-flush-period", 10*time.Second, "Duration to wait between writes of the status of Notifications and Silences with what happened with them")
 
 	opts := zap.Options{
 		Development: true,
@@ -225,35 +249,178 @@ func main() {
 	notificationsReg := notificationsRegistry.NewNotificationsRegistry()
 	watchersReg := watchersRegistry.NewWatchersRegistry()
 	sourcesReg := sourcesRegistry.NewSourcesRegistry()
+	silencesReg := silencesRegistry.NewSilencesRegistry()
 
-	// Setup Notifications controller
-	if err = (&notifications.NotificationReconciler{
+	// Expose the state of the registries along with the rest of metrics
+	ctrlmetrics.Registry.MustRegister(metrics.NewRegistriesCollector(watchersReg, sourcesReg))
+
+	// Messages that could not be delivered are kept only when a namespace is configured
+	var deadLetterStore deadletter.Store
+	if deadLetterNamespace != "" {
+		deadLetterStore = deadletter.NewConfigMapStore(mgr.GetClient(), mgr.GetAPIReader(),
+			deadLetterNamespace, deadLetterMaxEntries)
+	}
+
+	// Events are recorded on Notifications, and on watched objects when requested
+	eventRecorder := events.NewRecorder(mgr.GetEventRecorderFor("notifik"), recordObjectEvents)
+
+	// What happens with Notifications is kept in memory and written into their status periodically
+	statusTracker := tracker.NewStatusTracker(tracker.StatusTrackerOptions{
+		FlushPeriod: notificationStatusFlushPeriod,
+	}, tracker.StatusTrackerDependencies{
+		Context:               &globals.Application.Context,
+		Client:                mgr.GetClient(),
+		IntegrationsRegistry:  integrationsReg,
+		NotificationsRegistry: notificationsReg,
+		WatchersRegistry:      watchersReg,
+	})
+
+	// Init the manager in charge of delivering messages to integrations out of the informers.
+	// Its context is the one of the application, defined later
+	deliveryManager := delivery.NewDeliveryManager(delivery.DeliveryManagerDependencies{
+		Context:               &globals.Application.Context,
+		Client:                mgr.GetClient(),
+		IntegrationsRegistry:  integrationsReg,
+		NotificationsRegistry: notificationsReg,
+		EventRecorder:         eventRecorder,
+		StatusTracker:         statusTracker,
+		DeadLetterStore:       deadLetterStore,
+	})
+
+	// Informers are shared by watchers and sources watching the same resources.
+	// Secondary controllers are created before the Notifications one, which requests them to reconcile
+	sharedInformers := informers.NewSharedInformers(informers.SharedInformersOptions{
+		InformerDurationToResync: informerDurationToResync,
+	}, informers.SharedInformersDependencies{
+		Context: &globals.Application.Context,
+	})
+
+	// Init secondary controller to process coming events
+	watchersController := &watchers.WatchersController{
+		Client:  mgr.GetClient(),
+		Options: watchers.WatchersControllerOptions{},
+		Dependencies: watchers.WatchersControllerDependencies{
+			Context:               &globals.Application.Context,
+			DeliveryManager:       deliveryManager,
+			Informers:             sharedInformers,
+			EventRecorder:         eventRecorder,
+			StatusTracker:         statusTracker,
+			NotificationsRegistry: notificationsReg,
+			WatchersRegistry:      watchersReg,
+			SourcesRegistry:       sourcesReg,
+			SilencesRegistry:      silencesReg,
+		},
+	}
+
+	// Init secondary controller to accumulate extra-resources in a queryable pool
+	sourcesController := &sources.SourcesController{
+		Client:  mgr.GetClient(),
+		Options: sources.SourcesControllerOptions{},
+		Dependencies: sources.SourcesControllerDependencies{
+			Context:               &globals.Application.Context,
+			Informers:             sharedInformers,
+			NotificationsRegistry: notificationsReg,
+			SourcesRegistry:       sourcesReg,
+		},
+	}
+
+	// Setup Notifications and ClusterNotifications controllers. Both feed the same registry
+	notificationReconciler := notifications.NotificationReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 
-		Options: notifications.NotificationControllerOptions{},
+		Options: notifications.NotificationControllerOptions{
+			EnableTenancy: enableTenancy,
+		},
 		Dependencies: notifications.NotificationControllerDependencies{
 			NotificationsRegistry: notificationsReg,
+			DeliveryManager:       deliveryManager,
+			WatchersController:    watchersController,
+			SourcesController:     sourcesController,
 		},
-	}).SetupWithManager(mgr); err != nil {
+	}
+
+	if err = (&notificationReconciler).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Notification")
 		os.Exit(1)
 	}
 
-	// Setup Integrations controller
-	if err = (&integrations.IntegrationReconciler{
+	if err = (&notifications.ClusterNotificationReconciler{
+		NotificationReconciler: notificationReconciler,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterNotification")
+		os.Exit(1)
+	}
+
+	// Setup Integrations and ClusterIntegrations controllers. Both feed the same registry
+	integrationReconciler := integrations.IntegrationReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 
-		Options: integrations.IntegrationControllerOptions{},
+		Options: integrations.IntegrationControllerOptions{
+			HealthCheckPeriod: integrationHealthCheckPeriod,
+		},
 		Dependencies: integrations.IntegrationControllerDependencies{
 			IntegrationsRegistry: integrationsReg,
+			DeliveryManager:      deliveryManager,
 		},
-	}).SetupWithManager(mgr); err != nil {
+	}
+
+	if err = (&integrationReconciler).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Integration")
 		os.Exit(1)
 	}
 
+	if err = (&integrations.ClusterIntegrationReconciler{
+		IntegrationReconciler: integrationReconciler,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterIntegration")
+		os.Exit(1)
+	}
+
+	// Setup Silences controller
+	silenceReconciler := silences.SilenceReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+
+		Options: silences.SilenceControllerOptions{
+			StatusFlushPeriod: notificationStatusFlushPeriod,
+		},
+		Dependencies: silences.SilenceControllerDependencies{
+			SilencesRegistry: silencesReg,
+		},
+	}
+	if err = (&silenceReconciler).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Silence")
+		os.Exit(1)
+	}
+
+	if err = (&silences.ClusterSilenceReconciler{
+		SilenceReconciler: silenceReconciler,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterSilence")
+		os.Exit(1)
+	}
+
+	// Setup admission webhooks when requested, as they need certificates to work
+	if enableWebhooks {
+		if err = webhookv1alpha1.SetupNotificationWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Notification")
+			os.Exit(1)
+		}
+		if err = webhookv1alpha1.SetupIntegrationWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Integration")
+			os.Exit(1)
+		}
+		if err = webhookv1alpha1.SetupClusterNotificationWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterNotification")
+			os.Exit(1)
+		}
+		if err = webhookv1alpha1.SetupClusterIntegrationWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterIntegration")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -291,40 +458,18 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Init secondary controller to process coming events
-	watchersController := watchers.WatchersController{
-		Client: mgr.GetClient(),
-		Options: watchers.WatchersControllerOptions{
-			InformerDurationToResync: informerDurationToResync,
-		},
-		Dependencies: watchers.WatchersControllerDependencies{
-			Context:               &globals.Application.Context,
-			IntegrationsRegistry:  integrationsReg,
-			NotificationsRegistry: notificationsReg,
-			WatchersRegistry:      watchersReg,
-			SourcesRegistry:       sourcesReg,
-		},
-	}
-
 	setupLog.Info("starting watchers controller")
 	go watchersController.Start()
 
-	// Init secondary controller to accumulate extra-resources in a queryable pool
-	sourcesController := sources.SourcesController{
-		Client: mgr.GetClient(),
-		Options: sources.SourcesControllerOptions{
-			InformerDurationToResync: informerDurationToResync,
-		},
-		Dependencies: sources.SourcesControllerDependencies{
-			Context:               &globals.Application.Context,
-			NotificationsRegistry: notificationsReg,
-			SourcesRegistry:       sourcesReg,
-		},
-	}
-
 	setupLog.Info("starting sources controller")
 	go sourcesController.Start()
 
+	// Write what happens with Notifications into their status
+	go statusTracker.Start()
+
+	// Send again the messages that integrations would forget, such as firing alerts
+	go deliveryManager.Start()
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(globals.Application.Context); err != nil {
 		setupLog.Error(err, "problem running manager")
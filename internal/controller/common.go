@@ -3,8 +3,15 @@ package controller
 const (
 	ResourceFinalizer = "notifik.freepik.com/finalizer"
 
-	NotificationResourceType = "Notification"
-	IntegrationResourceType  = "Integration"
+	// ReplayDeadLettersAnnotation asks to replay the messages that could not be delivered to an Integration
+	ReplayDeadLettersAnnotation = "notifik.freepik.com/replay-dead-letters"
+
+	NotificationResourceType        = "Notification"
+	IntegrationResourceType         = "Integration"
+	SilenceResourceType             = "Silence"
+	ClusterNotificationResourceType = "ClusterNotification"
+	ClusterIntegrationResourceType  = "ClusterIntegration"
+	ClusterSilenceResourceType      = "ClusterSilence"
 
 	//
 	ResourceNotFoundError         = "%s '%s' resource not found. Ignoring since object must be deleted."
@@ -12,4 +19,5 @@ const (
 	ResourceFinalizersUpdateError = "Failed to update finalizer of %s '%s': %s"
 	ResourceConditionUpdateError  = "Failed to update the condition on %s '%s': %s"
 	ResourceReconcileError        = "Can not reconcile %s '%s': %s"
+	ResourceReplayError           = "Can not replay dead letters of %s '%s': %s"
 )
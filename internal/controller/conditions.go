@@ -25,13 +25,46 @@ const (
 	// ConditionTypeResourceSynced indicates that the target was synced or not
 	ConditionTypeResourceSynced = "ResourceSynced"
 
+	// ConditionTypeHealthy indicates whether the backend behind the resource is reachable or not
+	ConditionTypeHealthy = "Healthy"
+
+	// ConditionTypeReady indicates whether the resource is able to work or not
+	ConditionTypeReady = "Ready"
+
+	// ConditionTypeDelivering indicates whether the last message was delivered or not
+	ConditionTypeDelivering = "Delivering"
+
 	// Kubernetes error type
 	ConditionReasonKubernetesApiCallErrorType    = "KubernetesApiCallError"
 	ConditionReasonKubernetesApiCallErrorMessage = "Call to Kubernetes API failed. More info in logs."
 
+	// Invalid configuration error type
+	ConditionReasonInvalidConfigurationType    = "InvalidConfiguration"
+	ConditionReasonInvalidConfigurationMessage = "Resource configuration is not valid: %s"
+
 	// Success
 	ConditionReasonTargetSynced        = "TargetSynced"
 	ConditionReasonTargetSyncedMessage = "Target was successfully synced"
+
+	// Health
+	ConditionReasonBackendReachable          = "BackendReachable"
+	ConditionReasonBackendReachableMessage   = "Backend is reachable"
+	ConditionReasonBackendUnreachable        = "BackendUnreachable"
+	ConditionReasonBackendUnreachableMessage = "Backend is not reachable: %s"
+
+	// Readiness
+	ConditionReasonReady                      = "Ready"
+	ConditionReasonReadyMessage               = "Resource is ready"
+	ConditionReasonIntegrationNotFound        = "IntegrationNotFound"
+	ConditionReasonIntegrationNotFoundMessage = "Integration '%s' does not exist or is not valid"
+	ConditionReasonWatcherNotStarted          = "WatcherNotStarted"
+	ConditionReasonWatcherNotStartedMessage   = "Watcher for resource type '%s' is not started"
+
+	// Delivery
+	ConditionReasonDeliverySucceeded        = "DeliverySucceeded"
+	ConditionReasonDeliverySucceededMessage = "Last message was delivered"
+	ConditionReasonDeliveryFailed           = "DeliveryFailed"
+	ConditionReasonDeliveryFailedMessage    = "Last message could not be delivered: %s"
 )
 
 // NewCondition a set of default options for creating a Condition.
@@ -0,0 +1,167 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	//
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/controller"
+)
+
+// ClusterIntegrationReconciler reconciles a ClusterIntegration object.
+// ClusterIntegrations are registered as Integrations of their kind, so the rest is shared with the IntegrationReconciler
+type ClusterIntegrationReconciler struct {
+	IntegrationReconciler
+}
+
+// +kubebuilder:rbac:groups=notifik.freepik.com,resources=clusterintegrations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=notifik.freepik.com,resources=clusterintegrations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=notifik.freepik.com,resources=clusterintegrations/finalizers,verbs=update
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.2/pkg/reconcile
+func (r *ClusterIntegrationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	logger := log.FromContext(ctx)
+
+	// 1. Get the content of the ClusterIntegration
+	objectManifest := &v1alpha1.ClusterIntegration{}
+	err = r.Get(ctx, req.NamespacedName, objectManifest)
+
+	// 2. Check the existence inside the cluster
+	if err != nil {
+
+		// 2.1 It does NOT exist: manage removal
+		if err = client.IgnoreNotFound(err); err == nil {
+			logger.Info(fmt.Sprintf(controller.ResourceNotFoundError, controller.ClusterIntegrationResourceType, req.Name))
+			return result, err
+		}
+
+		// 2.2 Failed to get the resource, requeue the request
+		logger.Info(fmt.Sprintf(controller.ResourceRetrievalError, controller.ClusterIntegrationResourceType, req.Name, err.Error()))
+		return result, err
+	}
+
+	// The ClusterIntegration is handled as an Integration of its kind from here
+	integrationManifest := objectManifest.ToIntegration()
+
+	// 3. Check if the ClusterIntegration instance is marked to be deleted: indicated by the deletion timestamp being set
+	if !objectManifest.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(objectManifest, controller.ResourceFinalizer) {
+			err = r.ReconcileIntegration(ctx, watch.Deleted, integrationManifest)
+			if err != nil {
+				logger.Info(fmt.Sprintf(controller.ResourceReconcileError, controller.ClusterIntegrationResourceType, req.Name, err.Error()))
+				return result, err
+			}
+
+			// Remove the finalizers from ClusterIntegration
+			controllerutil.RemoveFinalizer(objectManifest, controller.ResourceFinalizer)
+			err = r.Update(ctx, objectManifest)
+			if err != nil {
+				logger.Info(fmt.Sprintf(controller.ResourceFinalizersUpdateError, controller.ClusterIntegrationResourceType, req.Name, err.Error()))
+			}
+		}
+		result = ctrl.Result{}
+		err = nil
+		return result, err
+	}
+
+	// 4. Add finalizer to the ClusterIntegration
+	if !controllerutil.ContainsFinalizer(objectManifest, controller.ResourceFinalizer) {
+		controllerutil.AddFinalizer(objectManifest, controller.ResourceFinalizer)
+		err = r.Update(ctx, objectManifest)
+		if err != nil {
+			return result, err
+		}
+		integrationManifest.ObjectMeta = *objectManifest.ObjectMeta.DeepCopy()
+	}
+
+	// 5. Update the status before the requeue, taking the conditions computed for the Integration
+	defer func() {
+		objectManifest.Status = integrationManifest.Status
+		err = r.Status().Update(ctx, objectManifest)
+		if err != nil {
+			logger.Info(fmt.Sprintf(controller.ResourceConditionUpdateError, controller.ClusterIntegrationResourceType, req.Name, err.Error()))
+		}
+	}()
+
+	// 6. The ClusterIntegration CR already exists: manage the update
+	err = r.ReconcileIntegration(ctx, watch.Modified, integrationManifest)
+	if err != nil {
+		logger.Info(fmt.Sprintf(controller.ResourceReconcileError, controller.ClusterIntegrationResourceType, req.Name, err.Error()))
+
+		// Invalid configurations will not be fixed by requeueing the resource
+		if errors.Is(err, ErrInvalidConfiguration) {
+			r.UpdateConditionInvalidConfiguration(integrationManifest, err)
+			return result, nil
+		}
+
+		r.UpdateConditionKubernetesApiCallFailure(integrationManifest)
+		return result, err
+	}
+
+	// 7. Success, update the status
+	r.UpdateConditionSuccess(integrationManifest)
+
+	// 8. Report whether the backend is reachable, and check it again later
+	healthErr := r.CheckIntegrationHealth(ctx, integrationManifest)
+	if healthErr != nil {
+		r.UpdateConditionUnhealthy(integrationManifest, healthErr)
+	} else {
+		r.UpdateConditionHealthy(integrationManifest)
+	}
+	result.RequeueAfter = r.Options.HealthCheckPeriod
+
+	// 9. Replay the messages that could not be delivered when requested, once the backend is reachable.
+	// Failed replays are tried again with the next health check
+	_, replayRequested := objectManifest.Annotations[controller.ReplayDeadLettersAnnotation]
+	if replayRequested && healthErr == nil {
+		replayErr := r.ReplayDeadLetters(ctx, integrationManifest, objectManifest)
+		if replayErr != nil {
+			logger.Info(fmt.Sprintf(controller.ResourceReplayError, controller.ClusterIntegrationResourceType, req.Name, replayErr.Error()))
+		}
+	}
+
+	return result, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterIntegrationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// Watch ClusterIntegrations
+		For(&v1alpha1.ClusterIntegration{}, builder.WithPredicates(getIntegrationPredicate())).
+		Named("clusterintegration").
+
+		// Watch Secrets and trigger reconciliation for ClusterIntegrations using them
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.getSecretRequests(v1alpha1.ClusterIntegrationKind))).
+		Complete(r)
+}
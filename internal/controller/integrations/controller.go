@@ -18,29 +18,39 @@ package integrations
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
+
 	//
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	//
 	"freepik.com/notifik/api/v1alpha1"
 	"freepik.com/notifik/internal/controller"
+	"freepik.com/notifik/internal/delivery"
 	"freepik.com/notifik/internal/registry/integrations"
 )
 
-type IntegrationControllerOptions struct{}
+type IntegrationControllerOptions struct {
+	// Duration to wait between checks of the backend behind an Integration
+	HealthCheckPeriod time.Duration
+}
 
 type IntegrationControllerDependencies struct {
 	IntegrationsRegistry *integrations.IntegrationsRegistry
+	DeliveryManager      *delivery.DeliveryManager
 }
 
 // IntegrationReconciler reconciles an Integration object
@@ -122,17 +132,43 @@ func (r *IntegrationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 	}()
 
-	// 6. The Notification CR already exists: manage the update
+	// 6. The Integration CR already exists: manage the update
 	err = r.ReconcileIntegration(ctx, watch.Modified, objectManifest)
 	if err != nil {
-		r.UpdateConditionKubernetesApiCallFailure(objectManifest)
 		logger.Info(fmt.Sprintf(controller.ResourceReconcileError, controller.IntegrationResourceType, req.Name, err.Error()))
+
+		// Invalid configurations will not be fixed by requeueing the resource
+		if errors.Is(err, ErrInvalidConfiguration) {
+			r.UpdateConditionInvalidConfiguration(objectManifest, err)
+			return result, nil
+		}
+
+		r.UpdateConditionKubernetesApiCallFailure(objectManifest)
 		return result, err
 	}
 
 	// 7. Success, update the status
 	r.UpdateConditionSuccess(objectManifest)
 
+	// 8. Report whether the backend is reachable, and check it again later
+	healthErr := r.CheckIntegrationHealth(ctx, objectManifest)
+	if healthErr != nil {
+		r.UpdateConditionUnhealthy(objectManifest, healthErr)
+	} else {
+		r.UpdateConditionHealthy(objectManifest)
+	}
+	result.RequeueAfter = r.Options.HealthCheckPeriod
+
+	// 9. Replay the messages that could not be delivered when requested, once the backend is reachable.
+	// Failed replays are tried again with the next health check
+	_, replayRequested := objectManifest.Annotations[controller.ReplayDeadLettersAnnotation]
+	if replayRequested && healthErr == nil {
+		replayErr := r.ReplayDeadLetters(ctx, objectManifest, objectManifest)
+		if replayErr != nil {
+			logger.Info(fmt.Sprintf(controller.ResourceReplayError, controller.IntegrationResourceType, req.Name, replayErr.Error()))
+		}
+	}
+
 	return result, err
 }
 
@@ -140,39 +176,56 @@ func (r *IntegrationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 func (r *IntegrationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		// Watch Integrations
-		For(&v1alpha1.Integration{}).
+		For(&v1alpha1.Integration{}, builder.WithPredicates(getIntegrationPredicate())).
 		Named("integration").
 
 		// Watch Secrets and trigger reconciliation for Integrations using them
-		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
-			requests := []reconcile.Request{}
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.getSecretRequests(v1alpha1.IntegrationKind))).
+		Complete(r)
+}
+
+// getIntegrationPredicate return the predicate filtering the events of Integrations. Only changes in the spec
+// or in the annotations requesting a replay are reconciled, as the status is also written on each delivery result
+func getIntegrationPredicate() predicate.Predicate {
+	return predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})
+}
+
+// getSecretRequests return a function mapping Secrets to the reconciliation requests
+// of the registered resources of the provided kind using them as credentials
+func (r *IntegrationReconciler) getSecretRequests(kind string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		requests := []reconcile.Request{}
+
+		secret, ok := obj.(*corev1.Secret)
+		if !ok {
+			return []reconcile.Request{}
+		}
 
-			secret, ok := obj.(*corev1.Secret)
-			if !ok {
-				return []reconcile.Request{}
+		integrationList := r.Dependencies.IntegrationsRegistry.GetIntegrations()
+		for _, integration := range integrationList {
+
+			// ClusterIntegrations are registered too, but reconciled by their own controller
+			if (integration.Kind == v1alpha1.ClusterIntegrationKind) != (kind == v1alpha1.ClusterIntegrationKind) {
+				continue
 			}
 
-			integrationList := r.Dependencies.IntegrationsRegistry.GetIntegrations()
-			for _, integration := range integrationList {
-
-				// Ignore integrations not asking for credentials
-				if !requestCredentials(integration) {
-					continue
-				}
-
-				//
-				if integration.Spec.Credentials.SecretRef.Name == secret.Name &&
-					integration.Spec.Credentials.SecretRef.Namespace == secret.Namespace {
-					requests = append(requests, reconcile.Request{
-						NamespacedName: types.NamespacedName{
-							Name:      integration.Name,
-							Namespace: integration.Namespace,
-						},
-					})
-				}
+			// Ignore integrations not asking for credentials
+			if !requestCredentials(integration) {
+				continue
 			}
 
-			return requests
-		})).
-		Complete(r)
+			//
+			if integration.Spec.Credentials.SecretRef.Name == secret.Name &&
+				integration.Spec.Credentials.SecretRef.Namespace == secret.Namespace {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      integration.Name,
+						Namespace: integration.Namespace,
+					},
+				})
+			}
+		}
+
+		return requests
+	}
 }
@@ -17,6 +17,9 @@ limitations under the License.
 package integrations
 
 import (
+	"fmt"
+
+	//
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	//
@@ -41,3 +44,32 @@ func (r *IntegrationReconciler) UpdateConditionKubernetesApiCallFailure(integrat
 
 	controller.UpdateCondition(&integration.Status.Conditions, condition)
 }
+
+func (r *IntegrationReconciler) UpdateConditionInvalidConfiguration(integration *v1alpha1.Integration, err error) {
+
+	//
+	condition := controller.NewCondition(controller.ConditionTypeResourceSynced, metav1.ConditionFalse,
+		controller.ConditionReasonInvalidConfigurationType,
+		fmt.Sprintf(controller.ConditionReasonInvalidConfigurationMessage, err.Error()))
+
+	controller.UpdateCondition(&integration.Status.Conditions, condition)
+}
+
+func (r *IntegrationReconciler) UpdateConditionHealthy(integration *v1alpha1.Integration) {
+
+	//
+	condition := controller.NewCondition(controller.ConditionTypeHealthy, metav1.ConditionTrue,
+		controller.ConditionReasonBackendReachable, controller.ConditionReasonBackendReachableMessage)
+
+	controller.UpdateCondition(&integration.Status.Conditions, condition)
+}
+
+func (r *IntegrationReconciler) UpdateConditionUnhealthy(integration *v1alpha1.Integration, err error) {
+
+	//
+	condition := controller.NewCondition(controller.ConditionTypeHealthy, metav1.ConditionFalse,
+		controller.ConditionReasonBackendUnreachable,
+		fmt.Sprintf(controller.ConditionReasonBackendUnreachableMessage, err.Error()))
+
+	controller.UpdateCondition(&integration.Status.Conditions, condition)
+}
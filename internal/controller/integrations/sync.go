@@ -27,10 +27,14 @@ import (
 
 	//
 	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	//
 	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/controller"
+	"freepik.com/notifik/internal/integrations"
+	integrationsRegistry "freepik.com/notifik/internal/registry/integrations"
 )
 
 const (
@@ -38,10 +42,14 @@ const (
 	//
 	integrationUpdatedMessage  = "An Integration was modified: will be updated into the internal registry"
 	integrationDeletionMessage = "An Integration was deleted: will be deleted from internal registry"
+	integrationReplayedMessage = "Dead letters of the Integration were queued again: %d"
 )
 
 var (
 	ExpansionPatternRegex = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+	// ErrInvalidConfiguration is returned when the driver of the Integration rejects its configuration
+	ErrInvalidConfiguration = errors.New("invalid integration configuration")
 )
 
 // ReconcileIntegration keeps internal Integration resources' registry up-to-date
@@ -57,6 +65,7 @@ func (r *IntegrationReconciler) ReconcileIntegration(ctx context.Context, eventT
 	if eventType == watch.Deleted {
 		logger.Info(integrationDeletionMessage)
 		r.Dependencies.IntegrationsRegistry.RemoveIntegration(integrationManifest)
+		r.Dependencies.DeliveryManager.RemoveQueue(integrationManifest)
 		return nil
 	}
 
@@ -70,36 +79,84 @@ func (r *IntegrationReconciler) ReconcileIntegration(ctx context.Context, eventT
 		r.Dependencies.IntegrationsRegistry.AddIntegration(integrationManifest)
 	}()
 
-	//
-	if !requestCredentials(integrationManifest) {
-		return nil
+	// Expand variables with values present in the secret when requested
+	if requestCredentials(integrationManifest) {
+
+		// Filled credentials must have name and namespace
+		if integrationManifest.Spec.Credentials.SecretRef.Name == "" ||
+			integrationManifest.Spec.Credentials.SecretRef.Namespace == "" {
+			return errors.New("integrations referencing credentials must have name and namespace")
+		}
+
+		//
+		credentialsSecret := &corev1.Secret{}
+		err = r.Get(ctx, types.NamespacedName{
+			Name:      integrationManifest.Spec.Credentials.SecretRef.Name,
+			Namespace: integrationManifest.Spec.Credentials.SecretRef.Namespace,
+		}, credentialsSecret)
+
+		if err != nil {
+			return errors.New(fmt.Sprintf("error fetching secret from Kubernetes: %v", err.Error()))
+		}
+
+		var varsExpandedIntegration *v1alpha1.Integration
+		varsExpandedIntegration, err = r.expandCredentials(integrationManifest, credentialsSecret)
+		if err != nil {
+			return errors.New(fmt.Sprintf("error expanding credentials: %v", err.Error()))
+		}
+
+		integrationManifest = varsExpandedIntegration
 	}
 
-	// Filled credentials must have name and namespace
-	if requestCredentials(integrationManifest) &&
-		(integrationManifest.Spec.Credentials.SecretRef.Name == "" ||
-			integrationManifest.Spec.Credentials.SecretRef.Namespace == "") {
-		return errors.New("integrations referencing credentials must have name and namespace")
+	// Reject what the driver can not handle, and stop using the Integration
+	// as requeueing it will not fix the configuration
+	err = integrations.ValidateIntegration(integrationManifest)
+	if err != nil {
+		r.Dependencies.IntegrationsRegistry.RemoveIntegration(integrationManifest)
+		r.Dependencies.DeliveryManager.RemoveQueue(integrationManifest)
+		return fmt.Errorf("%w: %s", ErrInvalidConfiguration, err.Error())
 	}
 
-	//
-	credentialsSecret := &corev1.Secret{}
-	err = r.Get(ctx, types.NamespacedName{
-		Name:      integrationManifest.Spec.Credentials.SecretRef.Name,
-		Namespace: integrationManifest.Spec.Credentials.SecretRef.Namespace,
-	}, credentialsSecret)
+	return nil
+}
 
+// CheckIntegrationHealth asks the driver whether the backend of the registered Integration is reachable.
+// The registered one is used as it has the credentials already expanded
+func (r *IntegrationReconciler) CheckIntegrationHealth(ctx context.Context, integrationManifest *v1alpha1.Integration) (err error) {
+	registeredIntegration, integrationFound := r.Dependencies.IntegrationsRegistry.GetIntegration(
+		integrationsRegistry.GetIntegrationKey(integrationManifest))
+	if !integrationFound {
+		return errors.New("integration not found in the internal registry")
+	}
+
+	return integrations.CheckHealth(ctx, registeredIntegration)
+}
+
+// ReplayDeadLetters queues again the messages that could not be delivered to the Integration,
+// and removes the annotation requesting it from the resource. It is an Integration or a ClusterIntegration
+func (r *IntegrationReconciler) ReplayDeadLetters(ctx context.Context, integrationManifest *v1alpha1.Integration,
+	resource client.Object) (err error) {
+	logger := log.FromContext(ctx)
+
+	replayed, err := r.Dependencies.DeliveryManager.Replay(ctx, integrationsRegistry.GetIntegrationKey(integrationManifest))
 	if err != nil {
-		return errors.New(fmt.Sprintf("error fetching secret from Kubernetes: %v", err.Error()))
+		return err
 	}
+	logger.Info(fmt.Sprintf(integrationReplayedMessage, replayed))
+
+	// The patch is applied on a copy, so the conditions being computed are not replaced by the stored ones
+	patchedResource := resource.DeepCopyObject().(client.Object)
+	patch := client.MergeFrom(resource.DeepCopyObject().(client.Object))
+	annotations := patchedResource.GetAnnotations()
+	delete(annotations, controller.ReplayDeadLettersAnnotation)
+	patchedResource.SetAnnotations(annotations)
 
-	// Expand variables with values present in the secret
-	varsExpandedIntegration, err := r.expandCredentials(integrationManifest, credentialsSecret)
+	err = r.Patch(ctx, patchedResource, patch)
 	if err != nil {
-		return errors.New(fmt.Sprintf("error expanding credentials: %v", err.Error()))
+		return err
 	}
+	resource.SetResourceVersion(patchedResource.GetResourceVersion())
 
-	integrationManifest = varsExpandedIntegration
 	return nil
 }
 
@@ -0,0 +1,146 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	//
+	"k8s.io/apimachinery/pkg/watch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/controller"
+)
+
+// ClusterNotificationReconciler reconciles a ClusterNotification object.
+// ClusterNotifications are registered as Notifications without namespace, so the rest is shared with the NotificationReconciler
+type ClusterNotificationReconciler struct {
+	NotificationReconciler
+}
+
+// +kubebuilder:rbac:groups=notifik.freepik.com,resources=clusternotifications,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=notifik.freepik.com,resources=clusternotifications/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=notifik.freepik.com,resources=clusternotifications/finalizers,verbs=update
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.2/pkg/reconcile
+func (r *ClusterNotificationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	logger := log.FromContext(ctx)
+
+	// 1. Get the content of the ClusterNotification
+	objectManifest := &v1alpha1.ClusterNotification{}
+	err = r.Get(ctx, req.NamespacedName, objectManifest)
+
+	// 2. Check the existence inside the cluster
+	if err != nil {
+
+		// 2.1 It does NOT exist: manage removal
+		if err = client.IgnoreNotFound(err); err == nil {
+			logger.Info(fmt.Sprintf(controller.ResourceNotFoundError, controller.ClusterNotificationResourceType, req.Name))
+			return result, err
+		}
+
+		// 2.2 Failed to get the resource, requeue the request
+		logger.Info(fmt.Sprintf(controller.ResourceRetrievalError, controller.ClusterNotificationResourceType, req.Name, err.Error()))
+		return result, err
+	}
+
+	// The ClusterNotification is handled as a Notification of its kind from here
+	notificationManifest := objectManifest.ToNotification()
+
+	// 3. Check if the ClusterNotification instance is marked to be deleted: indicated by the deletion timestamp being set
+	if !objectManifest.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(objectManifest, controller.ResourceFinalizer) {
+			// Delete Notification from WatcherPool
+			err = r.ReconcileNotification(ctx, watch.Deleted, notificationManifest)
+			if err != nil {
+				logger.Info(fmt.Sprintf(controller.ResourceReconcileError, controller.ClusterNotificationResourceType, req.Name, err.Error()))
+				return result, err
+			}
+
+			// Remove the finalizers on ClusterNotification CR
+			controllerutil.RemoveFinalizer(objectManifest, controller.ResourceFinalizer)
+			err = r.Update(ctx, objectManifest)
+			if err != nil {
+				logger.Info(fmt.Sprintf(controller.ResourceFinalizersUpdateError, controller.ClusterNotificationResourceType, req.Name, err.Error()))
+			}
+		}
+		result = ctrl.Result{}
+		err = nil
+		return result, err
+	}
+
+	// 4. Add finalizer to the ClusterNotification CR
+	if !controllerutil.ContainsFinalizer(objectManifest, controller.ResourceFinalizer) {
+		controllerutil.AddFinalizer(objectManifest, controller.ResourceFinalizer)
+		err = r.Update(ctx, objectManifest)
+		if err != nil {
+			return result, err
+		}
+		notificationManifest.ObjectMeta = *objectManifest.ObjectMeta.DeepCopy()
+	}
+
+	// 5. Update the status before the requeue, taking the conditions computed for the Notification
+	defer func() {
+		objectManifest.Status = notificationManifest.Status
+		err = r.Status().Update(ctx, objectManifest)
+		if err != nil {
+			logger.Info(fmt.Sprintf(controller.ResourceConditionUpdateError, controller.ClusterNotificationResourceType, req.Name, err.Error()))
+		}
+	}()
+
+	// 6. The ClusterNotification CR already exists: manage the update
+	err = r.ReconcileNotification(ctx, watch.Modified, notificationManifest)
+	if err != nil {
+		logger.Info(fmt.Sprintf(controller.ResourceReconcileError, controller.ClusterNotificationResourceType, req.Name, err.Error()))
+
+		// Invalid configurations will not be fixed by requeueing the resource
+		if errors.Is(err, ErrInvalidConfiguration) {
+			r.UpdateConditionInvalidConfiguration(notificationManifest, err)
+			return result, nil
+		}
+
+		r.UpdateConditionKubernetesApiCallFailure(notificationManifest)
+		return result, err
+	}
+
+	// 7. Success, update the status
+	r.UpdateConditionSuccess(notificationManifest)
+
+	return result, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Namespaces are not watched here, as the NotificationReconciler already syncs the controllers on their changes.
+// Only spec changes are reconciled, as the status is written periodically by the StatusTracker
+func (r *ClusterNotificationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ClusterNotification{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("clusternotification").
+		Complete(r)
+}
@@ -18,25 +18,51 @@ package notifications
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	//
 	"freepik.com/notifik/api/v1alpha1"
 	"freepik.com/notifik/internal/controller"
+	"freepik.com/notifik/internal/delivery"
 	"freepik.com/notifik/internal/registry/notifications"
+	"freepik.com/notifik/internal/tenancy"
+)
+
+const (
+	notificationsListingError = "Error listing the Notifications of namespace '%s': %s"
 )
 
-type NotificationControllerOptions struct{}
+type NotificationControllerOptions struct {
+	// EnableTenancy restricts Notifications to the namespaces and Integrations allowed for their namespace
+	EnableTenancy bool
+}
 
 type NotificationControllerDependencies struct {
 	NotificationsRegistry *notifications.NotificationsRegistry
+
+	// DeliveryManager tells the Integrations to forget the messages of Notifications that stop targeting them
+	DeliveryManager *delivery.DeliveryManager
+
+	// Controllers requested to reconcile their watchers and informers on Notifications changes
+	WatchersController controller.Syncer
+	SourcesController  controller.Syncer
 }
 
 // NotificationReconciler reconciles a Notification object
@@ -53,6 +79,8 @@ type NotificationReconciler struct {
 // +kubebuilder:rbac:groups=notifik.freepik.com,resources=notifications/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=notifik.freepik.com,resources=notifications/finalizers,verbs=update
 // +kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -121,8 +149,15 @@ func (r *NotificationReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// 6. The Notification CR already exists: manage the update
 	err = r.ReconcileNotification(ctx, watch.Modified, objectManifest)
 	if err != nil {
-		r.UpdateConditionKubernetesApiCallFailure(objectManifest)
 		logger.Info(fmt.Sprintf(controller.ResourceReconcileError, controller.NotificationResourceType, req.Name, err.Error()))
+
+		// Invalid configurations will not be fixed by requeueing the resource
+		if errors.Is(err, ErrInvalidConfiguration) {
+			r.UpdateConditionInvalidConfiguration(objectManifest, err)
+			return result, nil
+		}
+
+		r.UpdateConditionKubernetesApiCallFailure(objectManifest)
 		return result, err
 	}
 
@@ -133,9 +168,56 @@ func (r *NotificationReconciler) Reconcile(ctx context.Context, req ctrl.Request
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// Namespaces are watched too, as their labels decide the resources watched by Notifications with namespace selectors.
+// Only spec changes are reconciled, as the status is written periodically by the StatusTracker
 func (r *NotificationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Notification{}).
+		For(&v1alpha1.Notification{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&corev1.Namespace{}, handler.Funcs{
+			CreateFunc: func(context.Context, event.CreateEvent, workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+				r.syncControllers()
+			},
+			UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+				if !reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels()) {
+					r.syncControllers()
+				}
+
+				// Notifications of the namespace are reconciled again when their tenancy scope changes
+				oldAllowedNamespaces := e.ObjectOld.GetAnnotations()[tenancy.AllowedNamespacesAnnotation]
+				newAllowedNamespaces := e.ObjectNew.GetAnnotations()[tenancy.AllowedNamespacesAnnotation]
+				if r.Options.EnableTenancy && oldAllowedNamespaces != newAllowedNamespaces {
+					r.enqueueNamespaceNotifications(ctx, e.ObjectNew.GetName(), q)
+				}
+			},
+			DeleteFunc: func(context.Context, event.DeleteEvent, workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+				r.syncControllers()
+			},
+		}).
 		Named("notification").
 		Complete(r)
 }
+
+// enqueueNamespaceNotifications requests the reconciliation of all the Notifications in the namespace
+func (r *NotificationReconciler) enqueueNamespaceNotifications(ctx context.Context, namespace string,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	notificationList := &v1alpha1.NotificationList{}
+	err := r.List(ctx, notificationList, client.InNamespace(namespace))
+	if err != nil {
+		log.FromContext(ctx).Info(fmt.Sprintf(notificationsListingError, namespace, err.Error()))
+		return
+	}
+
+	for _, notification := range notificationList.Items {
+		q.Add(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&notification)})
+	}
+}
+
+// syncControllers requests the secondary controllers to reconcile their watchers and informers
+func (r *NotificationReconciler) syncControllers() {
+	if r.Dependencies.WatchersController != nil {
+		r.Dependencies.WatchersController.Sync()
+	}
+	if r.Dependencies.SourcesController != nil {
+		r.Dependencies.SourcesController.Sync()
+	}
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package notifications
 
 import (
+	"fmt"
+
+	//
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	//
@@ -41,3 +44,13 @@ func (r *NotificationReconciler) UpdateConditionKubernetesApiCallFailure(notific
 
 	controller.UpdateCondition(&notification.Status.Conditions, condition)
 }
+
+func (r *NotificationReconciler) UpdateConditionInvalidConfiguration(notification *v1alpha1.Notification, err error) {
+
+	//
+	condition := controller.NewCondition(controller.ConditionTypeResourceSynced, metav1.ConditionFalse,
+		controller.ConditionReasonInvalidConfigurationType,
+		fmt.Sprintf(controller.ConditionReasonInvalidConfigurationMessage, err.Error()))
+
+	controller.UpdateCondition(&notification.Status.Conditions, condition)
+}
@@ -18,14 +18,19 @@ package notifications
 
 import (
 	"context"
-	"strings"
+	"errors"
+	"fmt"
 
 	//
 	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	//
 	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/globals"
+	integrationsRegistry "freepik.com/notifik/internal/registry/integrations"
+	"freepik.com/notifik/internal/tenancy"
 )
 
 const (
@@ -35,23 +40,26 @@ const (
 	notificationDeletionMessage = "A Notification was deleted: will be deleted from internal registry"
 )
 
+var (
+	// ErrInvalidConfiguration is returned when the Notification can not be registered due to its configuration
+	ErrInvalidConfiguration = errors.New("invalid notification configuration")
+)
+
 // ReconcileNotification keeps internal Notification resources' registry up-to-date
 func (r *NotificationReconciler) ReconcileNotification(ctx context.Context, eventType watch.EventType, notificationManifest *v1alpha1.Notification) (err error) {
 	logger := log.FromContext(ctx)
 
-	watchedType := strings.Join([]string{
-		notificationManifest.Spec.Watch.Group,
-		notificationManifest.Spec.Watch.Version,
-		notificationManifest.Spec.Watch.Resource,
-		notificationManifest.Spec.Watch.Namespace,
-		notificationManifest.Spec.Watch.Name,
-	}, "/")
+	// Watchers and informers are reconciled after any change in the registry
+	defer r.syncControllers()
+
+	watchedType := globals.ResourceTypeFromWatch(&notificationManifest.Spec.Watch).String()
 
 	// Delete events
 	if eventType == watch.Deleted {
 		logger.Info(notificationDeletionMessage, "watcher", watchedType)
 
-		r.Dependencies.NotificationsRegistry.RemoveNotification(watchedType, notificationManifest)
+		r.Dependencies.NotificationsRegistry.RemoveNotification(notificationManifest)
+		r.Dependencies.DeliveryManager.Forget(notificationManifest, nil)
 		return nil
 	}
 
@@ -59,12 +67,71 @@ func (r *NotificationReconciler) ReconcileNotification(ctx context.Context, even
 	if eventType == watch.Modified {
 		logger.Info(notificationUpdatedMessage, "watcher", watchedType)
 
-		for _, registeredResourceType := range r.Dependencies.NotificationsRegistry.GetRegisteredResourceTypes() {
-			r.Dependencies.NotificationsRegistry.RemoveNotification(registeredResourceType, notificationManifest)
+		// Notifications are restricted to the namespaces and Integrations allowed for their namespace.
+		// ClusterNotifications are defined by cluster administrators, so they are never restricted
+		var scope *tenancy.Scope
+		if r.Options.EnableTenancy && notificationManifest.Kind != v1alpha1.ClusterNotificationKind {
+			scope, err = r.getTenancyScope(ctx, notificationManifest)
+			if err != nil {
+				r.Dependencies.NotificationsRegistry.RemoveNotification(notificationManifest)
+				r.Dependencies.DeliveryManager.Forget(notificationManifest, nil)
+				return err
+			}
+		}
+
+		// The previous version of the Notification is replaced at once, so no event sees it missing or unrestricted
+		err = r.Dependencies.NotificationsRegistry.AddNotification(watchedType, notificationManifest, scope)
+		if err != nil {
+			r.Dependencies.DeliveryManager.Forget(notificationManifest, nil)
+			return fmt.Errorf("%w: %s", ErrInvalidConfiguration, err.Error())
 		}
 
-		r.Dependencies.NotificationsRegistry.AddNotification(watchedType, notificationManifest)
+		// Integrations not targeted anymore would keep what they know about previous messages forever
+		targetedIntegrations := []string{}
+		for _, message := range notificationManifest.Spec.GetMessages() {
+			targetedIntegrations = append(targetedIntegrations, integrationsRegistry.GetReferenceKey(&message.Integration))
+		}
+		r.Dependencies.DeliveryManager.Forget(notificationManifest, targetedIntegrations)
 	}
 
 	return nil
 }
+
+// getTenancyScope return the scope of the Notification, checking that everything it uses is allowed by it.
+// Integrations that do not exist yet are checked again on delivery
+func (r *NotificationReconciler) getTenancyScope(ctx context.Context, notificationManifest *v1alpha1.Notification) (
+	scope *tenancy.Scope, err error) {
+	scope, err = tenancy.NewScope(ctx, r.Client, notificationManifest.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	err = scope.ValidateNotification(notificationManifest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidConfiguration, err.Error())
+	}
+
+	for _, message := range notificationManifest.Spec.GetMessages() {
+		// ClusterIntegrations are shared with all the namespaces
+		if message.Integration.Kind == v1alpha1.ClusterIntegrationKind {
+			continue
+		}
+
+		integrationName := message.Integration.Name
+		integration := &v1alpha1.Integration{}
+		err = r.Get(ctx, client.ObjectKey{Name: integrationName}, integration)
+		if err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if !scope.AllowsIntegration(integration) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidConfiguration,
+				fmt.Sprintf(tenancy.IntegrationNotSharedError, integrationName, scope.Namespace))
+		}
+	}
+
+	return scope, nil
+}
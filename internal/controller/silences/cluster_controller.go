@@ -0,0 +1,155 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package silences
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	//
+	"k8s.io/apimachinery/pkg/watch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/controller"
+	"freepik.com/notifik/internal/registry/silences"
+)
+
+// ClusterSilenceReconciler reconciles a ClusterSilence object.
+// ClusterSilences are registered as Silences without namespace, so the rest is shared with the SilenceReconciler
+type ClusterSilenceReconciler struct {
+	SilenceReconciler
+}
+
+// +kubebuilder:rbac:groups=notifik.freepik.com,resources=clustersilences,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=notifik.freepik.com,resources=clustersilences/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=notifik.freepik.com,resources=clustersilences/finalizers,verbs=update
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.2/pkg/reconcile
+func (r *ClusterSilenceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	logger := log.FromContext(ctx)
+
+	// 1. Get the content of the ClusterSilence
+	objectManifest := &v1alpha1.ClusterSilence{}
+	err = r.Get(ctx, req.NamespacedName, objectManifest)
+
+	// 2. Check the existence inside the cluster
+	if err != nil {
+
+		// 2.1 It does NOT exist: manage removal
+		if err = client.IgnoreNotFound(err); err == nil {
+			logger.Info(fmt.Sprintf(controller.ResourceNotFoundError, controller.ClusterSilenceResourceType, req.Name))
+			return result, err
+		}
+
+		// 2.2 Failed to get the resource, requeue the request
+		logger.Info(fmt.Sprintf(controller.ResourceRetrievalError, controller.ClusterSilenceResourceType, req.Name, err.Error()))
+		return result, err
+	}
+
+	// The ClusterSilence is handled as a Silence of its kind from here
+	silenceManifest := objectManifest.ToSilence()
+
+	// 3. Check if the ClusterSilence instance is marked to be deleted: indicated by the deletion timestamp being set
+	if !objectManifest.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(objectManifest, controller.ResourceFinalizer) {
+			// Delete ClusterSilence from the registry
+			err = r.ReconcileSilence(ctx, watch.Deleted, silenceManifest)
+			if err != nil {
+				logger.Info(fmt.Sprintf(controller.ResourceReconcileError, controller.ClusterSilenceResourceType, req.Name, err.Error()))
+				return result, err
+			}
+
+			// Remove the finalizers on ClusterSilence CR
+			controllerutil.RemoveFinalizer(objectManifest, controller.ResourceFinalizer)
+			err = r.Update(ctx, objectManifest)
+			if err != nil {
+				logger.Info(fmt.Sprintf(controller.ResourceFinalizersUpdateError, controller.ClusterSilenceResourceType, req.Name, err.Error()))
+			}
+		}
+		result = ctrl.Result{}
+		err = nil
+		return result, err
+	}
+
+	// 4. Add finalizer to the ClusterSilence CR
+	if !controllerutil.ContainsFinalizer(objectManifest, controller.ResourceFinalizer) {
+		controllerutil.AddFinalizer(objectManifest, controller.ResourceFinalizer)
+		err = r.Update(ctx, objectManifest)
+		if err != nil {
+			return result, err
+		}
+		silenceManifest.ObjectMeta = *objectManifest.ObjectMeta.DeepCopy()
+	}
+
+	// 5. Update the status before the requeue, taking the one computed for the Silence.
+	// It is only written when it changed, as every write triggers a reconciliation.
+	// Silenced messages taken from the registry are put back when they could not be written
+	var silencedRecord silences.SilencedRecord
+	defer func() {
+		if !silenceStatusChanged(&objectManifest.Status, &silenceManifest.Status) {
+			return
+		}
+		objectManifest.Status = silenceManifest.Status
+		err = r.Status().Update(ctx, objectManifest)
+		if err != nil {
+			logger.Info(fmt.Sprintf(controller.ResourceConditionUpdateError, controller.ClusterSilenceResourceType, req.Name, err.Error()))
+			r.Dependencies.SilencesRegistry.RestoreSilenced(silenceManifest, silencedRecord)
+		}
+	}()
+
+	// 6. The ClusterSilence CR already exists: manage the update
+	err = r.ReconcileSilence(ctx, watch.Modified, silenceManifest)
+	if err != nil {
+		logger.Info(fmt.Sprintf(controller.ResourceReconcileError, controller.ClusterSilenceResourceType, req.Name, err.Error()))
+
+		// Invalid configurations will not be fixed by requeueing the resource
+		if errors.Is(err, ErrInvalidConfiguration) {
+			r.UpdateConditionInvalidConfiguration(silenceManifest, err)
+			return result, nil
+		}
+
+		r.UpdateConditionKubernetesApiCallFailure(silenceManifest)
+		return result, err
+	}
+
+	// 7. Success, update the status with the messages silenced, and do it again later
+	r.UpdateConditionSuccess(silenceManifest)
+	silencedRecord = r.UpdateSilencedStatus(silenceManifest)
+	result.RequeueAfter = r.Options.StatusFlushPeriod
+
+	return result, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Only spec changes are reconciled, as the status is written periodically by requeueing the ClusterSilence
+func (r *ClusterSilenceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ClusterSilence{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("clustersilence").
+		Complete(r)
+}
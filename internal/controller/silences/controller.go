@@ -0,0 +1,165 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package silences
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	//
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/controller"
+	"freepik.com/notifik/internal/registry/silences"
+)
+
+type SilenceControllerOptions struct {
+	// Duration to wait between writes of the status of a Silence with the messages it silenced
+	StatusFlushPeriod time.Duration
+}
+
+type SilenceControllerDependencies struct {
+	SilencesRegistry *silences.SilencesRegistry
+}
+
+// SilenceReconciler reconciles a Silence object
+type SilenceReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	//
+	Options      SilenceControllerOptions
+	Dependencies SilenceControllerDependencies
+}
+
+// +kubebuilder:rbac:groups=notifik.freepik.com,resources=silences,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=notifik.freepik.com,resources=silences/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=notifik.freepik.com,resources=silences/finalizers,verbs=update
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.2/pkg/reconcile
+func (r *SilenceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	logger := log.FromContext(ctx)
+
+	// 1. Get the content of the silence
+	objectManifest := &v1alpha1.Silence{}
+	err = r.Get(ctx, req.NamespacedName, objectManifest)
+
+	// 2. Check the existence inside the cluster
+	if err != nil {
+
+		// 2.1 It does NOT exist: manage removal
+		if err = client.IgnoreNotFound(err); err == nil {
+			logger.Info(fmt.Sprintf(controller.ResourceNotFoundError, controller.SilenceResourceType, req.Name))
+			return result, err
+		}
+
+		// 2.2 Failed to get the resource, requeue the request
+		logger.Info(fmt.Sprintf(controller.ResourceRetrievalError, controller.SilenceResourceType, req.Name, err.Error()))
+		return result, err
+	}
+
+	// 3. Check if the silence instance is marked to be deleted: indicated by the deletion timestamp being set
+	if !objectManifest.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(objectManifest, controller.ResourceFinalizer) {
+			// Delete Silence from the registry
+			err = r.ReconcileSilence(ctx, watch.Deleted, objectManifest)
+			if err != nil {
+				logger.Info(fmt.Sprintf(controller.ResourceReconcileError, controller.SilenceResourceType, req.Name, err.Error()))
+				return result, err
+			}
+
+			// Remove the finalizers on silence CR
+			controllerutil.RemoveFinalizer(objectManifest, controller.ResourceFinalizer)
+			err = r.Update(ctx, objectManifest)
+			if err != nil {
+				logger.Info(fmt.Sprintf(controller.ResourceFinalizersUpdateError, controller.SilenceResourceType, req.Name, err.Error()))
+			}
+		}
+		result = ctrl.Result{}
+		err = nil
+		return result, err
+	}
+
+	// 4. Add finalizer to the silence CR
+	if !controllerutil.ContainsFinalizer(objectManifest, controller.ResourceFinalizer) {
+		controllerutil.AddFinalizer(objectManifest, controller.ResourceFinalizer)
+		err = r.Update(ctx, objectManifest)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	// 5. Update the status before the requeue, only when it changed, as every write triggers a reconciliation.
+	// Silenced messages taken from the registry are put back when they could not be written
+	var silencedRecord silences.SilencedRecord
+	previousStatus := objectManifest.Status.DeepCopy()
+	defer func() {
+		if !silenceStatusChanged(previousStatus, &objectManifest.Status) {
+			return
+		}
+		err = r.Status().Update(ctx, objectManifest)
+		if err != nil {
+			logger.Info(fmt.Sprintf(controller.ResourceConditionUpdateError, controller.SilenceResourceType, req.Name, err.Error()))
+			r.Dependencies.SilencesRegistry.RestoreSilenced(objectManifest, silencedRecord)
+		}
+	}()
+
+	// 6. The Silence CR already exists: manage the update
+	err = r.ReconcileSilence(ctx, watch.Modified, objectManifest)
+	if err != nil {
+		logger.Info(fmt.Sprintf(controller.ResourceReconcileError, controller.SilenceResourceType, req.Name, err.Error()))
+
+		// Invalid configurations will not be fixed by requeueing the resource
+		if errors.Is(err, ErrInvalidConfiguration) {
+			r.UpdateConditionInvalidConfiguration(objectManifest, err)
+			return result, nil
+		}
+
+		r.UpdateConditionKubernetesApiCallFailure(objectManifest)
+		return result, err
+	}
+
+	// 7. Success, update the status with the messages silenced, and do it again later
+	r.UpdateConditionSuccess(objectManifest)
+	silencedRecord = r.UpdateSilencedStatus(objectManifest)
+	result.RequeueAfter = r.Options.StatusFlushPeriod
+
+	return result, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Only spec changes are reconciled, as the status is written periodically by requeueing the Silence
+func (r *SilenceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.Silence{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("silence").
+		Complete(r)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package silences
+
+import (
+	"fmt"
+	"time"
+
+	//
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/controller"
+	"freepik.com/notifik/internal/registry/silences"
+)
+
+func (r *SilenceReconciler) UpdateConditionSuccess(silence *v1alpha1.Silence) {
+
+	//
+	condition := controller.NewCondition(controller.ConditionTypeResourceSynced, metav1.ConditionTrue,
+		controller.ConditionReasonTargetSynced, controller.ConditionReasonTargetSyncedMessage)
+
+	controller.UpdateCondition(&silence.Status.Conditions, condition)
+}
+
+func (r *SilenceReconciler) UpdateConditionKubernetesApiCallFailure(silence *v1alpha1.Silence) {
+
+	//
+	condition := controller.NewCondition(controller.ConditionTypeResourceSynced, metav1.ConditionFalse,
+		controller.ConditionReasonKubernetesApiCallErrorType, controller.ConditionReasonKubernetesApiCallErrorMessage)
+
+	controller.UpdateCondition(&silence.Status.Conditions, condition)
+}
+
+func (r *SilenceReconciler) UpdateConditionInvalidConfiguration(silence *v1alpha1.Silence, err error) {
+
+	//
+	condition := controller.NewCondition(controller.ConditionTypeResourceSynced, metav1.ConditionFalse,
+		controller.ConditionReasonInvalidConfigurationType,
+		fmt.Sprintf(controller.ConditionReasonInvalidConfigurationMessage, err.Error()))
+
+	controller.UpdateCondition(&silence.Status.Conditions, condition)
+}
+
+// UpdateSilencedStatus writes whether the Silence is active, and adds the messages it silenced since the last time.
+// The messages added are returned, so they can be restored when the status can not be written
+func (r *SilenceReconciler) UpdateSilencedStatus(silence *v1alpha1.Silence) (record silences.SilencedRecord) {
+	compiled, found := r.Dependencies.SilencesRegistry.GetSilence(silence.Namespace, silence.Name)
+	if !found {
+		return record
+	}
+	silence.Status.Active = compiled.IsActive(time.Now())
+
+	record = r.Dependencies.SilencesRegistry.TakeSilenced(silence)
+	if record.Count == 0 {
+		return record
+	}
+
+	silence.Status.SilencedCount += record.Count
+	silence.Status.LastSilencedTime = &metav1.Time{Time: record.LastTime}
+
+	return record
+}
+
+// silenceStatusChanged checks whether the status must be written again.
+// The transition time of the conditions is ignored, as it is refreshed on every reconciliation
+func silenceStatusChanged(previous, current *v1alpha1.SilenceStatus) bool {
+	if previous.Active != current.Active || previous.SilencedCount != current.SilencedCount {
+		return true
+	}
+	if len(previous.Conditions) != len(current.Conditions) {
+		return true
+	}
+
+	for i := range current.Conditions {
+		if previous.Conditions[i].Type != current.Conditions[i].Type ||
+			previous.Conditions[i].Status != current.Conditions[i].Status ||
+			previous.Conditions[i].Reason != current.Conditions[i].Reason ||
+			previous.Conditions[i].Message != current.Conditions[i].Message {
+			return true
+		}
+	}
+	return false
+}
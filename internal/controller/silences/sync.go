@@ -0,0 +1,68 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package silences
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	//
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+)
+
+const (
+
+	//
+	silenceUpdatedMessage  = "A Silence was modified: will be updated into the internal registry"
+	silenceDeletionMessage = "A Silence was deleted: will be deleted from internal registry"
+)
+
+var (
+	// ErrInvalidConfiguration is returned when the Silence can not be registered due to its configuration
+	ErrInvalidConfiguration = errors.New("invalid silence configuration")
+)
+
+// ReconcileSilence keeps internal Silence resources' registry up-to-date
+func (r *SilenceReconciler) ReconcileSilence(ctx context.Context, eventType watch.EventType, silenceManifest *v1alpha1.Silence) (err error) {
+	logger := log.FromContext(ctx)
+
+	// Delete events
+	if eventType == watch.Deleted {
+		logger.Info(silenceDeletionMessage)
+
+		r.Dependencies.SilencesRegistry.RemoveSilence(silenceManifest)
+		return nil
+	}
+
+	// Create/Update events
+	if eventType == watch.Modified {
+		logger.Info(silenceUpdatedMessage)
+
+		// Messages silenced by the previous version of the Silence are kept
+		err = r.Dependencies.SilencesRegistry.AddSilence(silenceManifest)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidConfiguration, err.Error())
+		}
+	}
+
+	return nil
+}
@@ -20,56 +20,51 @@ import (
 	"context"
 	"fmt"
 	"slices"
-	"strings"
 	"time"
 
 	//
-	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
-	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/tools/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	//
+	"freepik.com/notifik/internal/controller"
 	"freepik.com/notifik/internal/globals"
+	"freepik.com/notifik/internal/informers"
 	notificationsRegistry "freepik.com/notifik/internal/registry/notifications"
 	sourcesRegistry "freepik.com/notifik/internal/registry/sources"
 )
 
 const (
-	// secondsToCheckInformerAck is the number of seconds before checking
-	// whether an informer is started or not during informers' reconciling process
-	secondsToCheckInformerAck = 10 * time.Second
+	// durationToResyncInformers is the maximum time between reconciliations of informers.
+	// They are reconciled on demand, so this only retries the informers that failed to start
+	durationToResyncInformers = 10 * time.Second
 
-	// secondsToReconcileInformersAgain is the number of seconds to wait
-	// between the moment of launching informers, and repeating this process
-	// (avoid the spam, mate)
-	secondsToReconcileInformersAgain = 2 * time.Second
+	// durationToStopInformers is the maximum time to wait for a informer to stop when it is disabled
+	durationToStopInformers = 10 * time.Second
 
 	//
 	controllerContextFinishedMessage = "SourcesController finished by context"
 	controllerInformerStartedMessage = "Informer for '%s' has been started"
+	controllerInformerSyncedMessage  = "Informer for '%s' has been synced"
 	controllerInformerKilledMessage  = "Informer for resource type '%s' killed by StopSignal"
 
 	watchedObjectParseError         = "Impossible to process triggered object: %s"
-	resourceInformerLaunchingError  = "Impossible to start informer for resource type: %s"
+	resourceInformerDisablingError  = "Impossible to disable informer for resource type: %s"
+	resourceTypesExpansionError     = "Failed expanding some resource types with namespace selectors, they are skipped: %s"
 	resourceInformerGvrParsingError = "Failed to parse GVR from resourceType. Does it look like {group}/{version}/{resource}?"
 )
 
 // SourcesControllerOptions represents available options that can be passed to SourcesController on start
-type SourcesControllerOptions struct {
-	// Duration to wait until resync all the objects
-	InformerDurationToResync time.Duration
-}
+type SourcesControllerOptions struct{}
 
 type SourcesControllerDependencies struct {
 	Context *context.Context
 
 	//
+	Informers             *informers.SharedInformers
 	NotificationsRegistry *notificationsRegistry.NotificationsRegistry
 	SourcesRegistry       *sourcesRegistry.SourcesRegistry
 }
@@ -82,146 +77,110 @@ type SourcesController struct {
 
 	Options      SourcesControllerOptions
 	Dependencies SourcesControllerDependencies
-}
-
-// informersCleanerWorker review the sources types of Notifications registry in the background.
-// It disables the informers that are not needed and delete them from sources registry
-// This function is intended to be used as goroutine
-func (r *SourcesController) informersCleanerWorker() {
-	logger := log.FromContext(*r.Dependencies.Context)
-	logger.Info("Starting informers cleaner worker")
 
-	for {
-		//
-		referentCandidates := r.Dependencies.NotificationsRegistry.GetRegisteredExtraResourcesTypes()
-		evaluableCandidates := r.Dependencies.SourcesRegistry.GetRegisteredResourceTypes()
-
-		for _, resourceType := range evaluableCandidates {
-			if !slices.Contains(referentCandidates, resourceType) {
-				err := r.Dependencies.SourcesRegistry.DisableInformer(resourceType)
-				if err != nil {
-					logger.WithValues("resourceType", resourceType).
-						Info("Failed disabling sources informer")
-				}
-			}
-		}
+	// syncTrigger requests the reconciliation of the informers
+	syncTrigger controller.Trigger
+}
 
-		time.Sleep(5 * time.Second)
-	}
+// Sync requests the reconciliation of the informers, so they are started or stopped as Notifications
+// or namespaces change. It never blocks, and requests made while one is pending are merged
+func (r *SourcesController) Sync() {
+	r.syncTrigger.Fire()
 }
 
 // Start launches the SourcesController and keeps it alive
-// It kills the controller on application's context death, and rerun the process when failed
+// It kills the controller on application's context death. Informers are reconciled when requested,
+// and periodically to retry those that failed to start
 func (r *SourcesController) Start() {
 	logger := log.FromContext(*r.Dependencies.Context)
 
-	// Start cleaner for dead informers
-	go r.informersCleanerWorker()
-
 	// Keep your controller alive
 	for {
+		r.reconcileInformers()
+
 		select {
 		case <-(*r.Dependencies.Context).Done():
 			logger.Info(controllerContextFinishedMessage)
 			return
-		default:
-			r.reconcileInformers()
-			time.Sleep(secondsToReconcileInformersAgain)
+		case <-r.syncTrigger.C():
+		case <-time.After(durationToResyncInformers):
 		}
 	}
 }
 
-// reconcileInformers checks each registered extra-resource type and triggers informers
-// for those that are not already started.
+// reconcileInformers launches informers for the extra-resource types of the Notifications that have none,
+// and disables those that are not needed anymore
 func (r *SourcesController) reconcileInformers() {
 	logger := log.FromContext(*r.Dependencies.Context)
 
-	for _, resourceType := range r.Dependencies.NotificationsRegistry.GetRegisteredExtraResourcesTypes() {
+	// Resource types using a namespace selector are watched with one informer per matching namespace
+	// Those that can not be expanded are skipped, keeping what is already running for them
+	resourceTypes, failedResourceTypes, err := globals.ExpandResourceTypeNames(*r.Dependencies.Context, r.Client,
+		r.Dependencies.NotificationsRegistry.GetRegisteredExtraResourcesTypes())
+	if err != nil {
+		logger.Info(fmt.Sprintf(resourceTypesExpansionError, err))
+	}
+
+	for _, resourceType := range resourceTypes {
+		if _, informerExists := r.Dependencies.SourcesRegistry.GetInformer(resourceType); informerExists {
+			continue
+		}
 
-		_, informerExists := r.Dependencies.SourcesRegistry.GetInformer(resourceType)
+		// Informers are registered before launching them, so they are never launched twice
+		informer := r.Dependencies.SourcesRegistry.RegisterInformer(resourceType)
+		go r.launchInformerForType(resourceType, informer)
+	}
 
-		// Avoid wasting CPU for nothing
-		if informerExists && r.Dependencies.SourcesRegistry.IsStarted(resourceType) {
+	// Disabling waits for the informers to stop, so it is done in the background
+	for _, resourceType := range r.Dependencies.SourcesRegistry.GetRegisteredResourceTypes() {
+		if slices.Contains(resourceTypes, resourceType) ||
+			slices.Contains(failedResourceTypes, globals.UnexpandedResourceTypeName(resourceType)) {
 			continue
 		}
 
-		//
-		if !informerExists || !r.Dependencies.SourcesRegistry.IsStarted(resourceType) {
-			go r.launchInformerForType(resourceType)
+		go func() {
+			ctx, cancel := context.WithTimeout(*r.Dependencies.Context, durationToStopInformers)
+			defer cancel()
 
-			// Wait for the just started informer to ACK itself
-			time.Sleep(secondsToCheckInformerAck)
-			if !r.Dependencies.SourcesRegistry.IsStarted(resourceType) {
-				logger.Info(fmt.Sprintf(resourceInformerLaunchingError, resourceType))
+			err := r.Dependencies.SourcesRegistry.DisableInformer(ctx, resourceType)
+			if err != nil {
+				logger.WithValues("error", err).Info(fmt.Sprintf(resourceInformerDisablingError, resourceType))
 			}
-		}
+		}()
 	}
 }
 
-// launchInformerForType creates and runs a Kubernetes informer for the specified
-// resource type, and triggers processing for each event
-func (r *SourcesController) launchInformerForType(resourceType sourcesRegistry.ResourceTypeName) {
+// launchInformerForType subscribes to the shared informer of the specified resource type,
+// and triggers processing for each event. The informer is started once it has received all the existing objects.
+// Informers failing to subscribe are disabled, so they are launched again on next reconciliation
+func (r *SourcesController) launchInformerForType(resourceType sourcesRegistry.ResourceTypeName,
+	informer *sourcesRegistry.SourcesInformer) {
 	logger := log.FromContext(*r.Dependencies.Context)
-
-	informer, informerExists := r.Dependencies.SourcesRegistry.GetInformer(resourceType)
-	if !informerExists {
-		informer = r.Dependencies.SourcesRegistry.RegisterInformer(resourceType)
-	}
-
 	logger.Info(fmt.Sprintf(controllerInformerStartedMessage, resourceType))
 
-	// Trigger ACK flag for informer that is launching
-	// Hey, this informer is blocking, so ACK is only disabled if the informer becomes dead
-	_ = r.Dependencies.SourcesRegistry.SetStarted(resourceType, true)
-	defer func() {
-		_ = r.Dependencies.SourcesRegistry.SetStarted(resourceType, false)
-	}()
-
-	// Extract GVR + Namespace + Name from watched type:
-	// {group}/{version}/{resource}/{namespace}/{name}
-	GVRNN := strings.Split(resourceType, "/")
-	if len(GVRNN) != 5 {
-		logger.Info(resourceInformerGvrParsingError)
-		return
-	}
-	resourceGVR := schema.GroupVersionResource{
-		Group:    GVRNN[0],
-		Version:  GVRNN[1],
-		Resource: GVRNN[2],
-	}
-
-	// Include the namespace when defined by the user (used as filter)
-	namespace := corev1.NamespaceAll
-	if GVRNN[3] != "" {
-		namespace = GVRNN[3]
-	}
-
-	// Include the name when defined by the user (used as filter)
-	name := GVRNN[4]
-
-	var listOptionsFunc dynamicinformer.TweakListOptionsFunc = func(options *metav1.ListOptions) {}
-	if name != "" {
-		listOptionsFunc = func(options *metav1.ListOptions) {
-			options.FieldSelector = "metadata.name=" + name
-		}
-	}
+	// ACK flag is only enabled once synced, and disabled if the informer becomes dead.
+	// Done is closed once everything is released, so disabling the informer waits for it
+	defer r.Dependencies.SourcesRegistry.FinishInformer(informer)
 
 	// Listen to stop signal to kill this informer just in case it's needed
 	stopCh := make(chan struct{})
 
 	go func() {
-		<-informer.StopSignal
+		select {
+		case <-informer.StopSignal:
+		case <-(*r.Dependencies.Context).Done():
+		}
 		close(stopCh)
 		logger.Info(fmt.Sprintf(controllerInformerKilledMessage, resourceType))
 	}()
 
-	// Define our informer TODO
-	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(globals.Application.KubeRawClient,
-		r.Options.InformerDurationToResync, namespace, listOptionsFunc)
-
-	// Create an informer. This is a special type of client-go informer that includes
-	// mechanisms to hide disconnections, handle reconnections, and cache watched objects
-	kubeInformer := factory.ForResource(resourceGVR).Informer()
+	// Extract GVR, namespace, name and selectors from the resource type
+	parsedResourceType, err := globals.ParseResourceTypeName(resourceType)
+	if err != nil {
+		logger.Info(resourceInformerGvrParsingError, "error", err)
+		r.Dependencies.SourcesRegistry.RemoveInformer(resourceType, informer)
+		return
+	}
 
 	// Register functions to handle different types of events
 	handlers := cache.ResourceEventHandlerFuncs{
@@ -254,13 +213,26 @@ func (r *SourcesController) launchInformerForType(resourceType sourcesRegistry.R
 		},
 	}
 
-	_, err := kubeInformer.AddEventHandler(handlers)
+	// Subscribe to the informer shared by all the resource types with the same GVR and namespace.
+	// It is filtered by the name and selectors of this resource type
+	subscription, err := r.Dependencies.Informers.Subscribe(parsedResourceType, handlers)
 	if err != nil {
-		logger.Error(err, "Error adding handling functions for events to an informer")
+		logger.Error(err, "Error subscribing to a shared informer")
+		r.Dependencies.SourcesRegistry.RemoveInformer(resourceType, informer)
 		return
 	}
+	defer func() {
+		_ = subscription.Unsubscribe()
+	}()
+
+	// The informer is started once it has received the initial list of objects
+	if !cache.WaitForCacheSync(stopCh, subscription.HasSynced) {
+		return
+	}
+	_ = r.Dependencies.SourcesRegistry.SetStarted(resourceType, true)
+	logger.Info(fmt.Sprintf(controllerInformerSyncedMessage, resourceType))
 
-	kubeInformer.Run(stopCh)
+	<-stopCh
 }
 
 // processEvent process an event coming from a triggered extra-resource type.
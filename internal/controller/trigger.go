@@ -0,0 +1,54 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+)
+
+// Syncer is implemented by the controllers that can be requested to reconcile their state
+type Syncer interface {
+	Sync()
+}
+
+// Trigger coalesces requests to run a process: requests made while another one is pending are merged into it.
+// Its zero value is ready to be used
+type Trigger struct {
+	once sync.Once
+	ch   chan struct{}
+}
+
+// Fire requests the process to run. It never blocks
+func (t *Trigger) Fire() {
+	select {
+	case t.channel() <- struct{}{}:
+	default:
+	}
+}
+
+// C return the channel receiving the requests
+func (t *Trigger) C() <-chan struct{} {
+	return t.channel()
+}
+
+// channel return the channel of the requests, creating it on first use
+func (t *Trigger) channel() chan struct{} {
+	t.once.Do(func() {
+		t.ch = make(chan struct{}, 1)
+	})
+	return t.ch
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watchers
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	//
+	"github.com/google/cel-go/cel"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/expression"
+	notificationsRegistry "freepik.com/notifik/internal/registry/notifications"
+)
+
+const (
+	// templateNoValue is what Go templates render for missing keys
+	templateNoValue = "<no value>"
+
+	//
+	conditionErrorMessage         = "condition '%s': %s"
+	conditionRegexErrorMessage    = "condition '%s': invalid regular expression: %s"
+	conditionNumberErrorMessage   = "condition '%s': '%s' is not a number or a quantity"
+	conditionOperatorErrorMessage = "condition '%s': unknown operator '%s'"
+)
+
+// evaluateConditions checks whether the object meets the conditions of the Notification.
+// All the conditions must be met, and at least one of the groups in 'anyOf' when defined.
+// Conditions that can not be evaluated are not met, and the first error is returned.
+// CEL expressions, templates and regular expressions are evaluated using those compiled when the Notification
+// was registered
+func evaluateConditions(notification *v1alpha1.Notification, compiled *notificationsRegistry.CompiledNotification,
+	data map[string]interface{}) (met bool, err error) {
+	met, err = evaluateConditionList(notification.Spec.Conditions, compiled, data)
+	if !met || len(notification.Spec.AnyOf) == 0 {
+		return met, err
+	}
+
+	var firstErr error
+	for _, group := range notification.Spec.AnyOf {
+		groupMet, groupErr := evaluateConditionList(group.Conditions, compiled, data)
+		if groupMet {
+			return true, nil
+		}
+		if firstErr == nil {
+			firstErr = groupErr
+		}
+	}
+
+	return false, firstErr
+}
+
+// evaluateConditionList checks whether all the conditions of the list are met
+func evaluateConditionList(conditions []v1alpha1.NotificationCondition, compiled *notificationsRegistry.CompiledNotification,
+	data map[string]interface{}) (met bool, err error) {
+	for _, condition := range conditions {
+		if condition.Expression != "" {
+			met, err = evaluateExpression(&condition, compiled.Programs, data)
+		} else {
+			met, err = evaluateCondition(&condition, compiled, data)
+		}
+
+		if !met {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// evaluateCondition renders the key of the condition and compares it using the operator of the condition
+func evaluateCondition(condition *v1alpha1.NotificationCondition, compiled *notificationsRegistry.CompiledNotification,
+	data map[string]interface{}) (met bool, err error) {
+	parsedKey, err := compiled.Templates.Evaluate(condition.Key, data)
+	if err != nil {
+		return false, fmt.Errorf(conditionErrorMessage, condition.Name, err)
+	}
+
+	switch condition.Operator {
+	case "", v1alpha1.NotificationConditionOperatorEqual:
+		return parsedKey == condition.Value, nil
+
+	case v1alpha1.NotificationConditionOperatorNotEqual:
+		return parsedKey != condition.Value, nil
+
+	case v1alpha1.NotificationConditionOperatorIn:
+		return slices.Contains(condition.Values, parsedKey), nil
+
+	case v1alpha1.NotificationConditionOperatorNotIn:
+		return !slices.Contains(condition.Values, parsedKey), nil
+
+	case v1alpha1.NotificationConditionOperatorExists:
+		return parsedKey != "" && parsedKey != templateNoValue, nil
+
+	case v1alpha1.NotificationConditionOperatorMatches:
+		// Regular expressions are compiled on registration, so this only happens when the registry is being updated
+		expression, found := compiled.Regexps[condition.Value]
+		if !found {
+			expression, err = regexp.Compile(condition.Value)
+			if err != nil {
+				return false, fmt.Errorf(conditionRegexErrorMessage, condition.Name, err)
+			}
+		}
+		return expression.MatchString(parsedKey), nil
+
+	case v1alpha1.NotificationConditionOperatorGreaterThan, v1alpha1.NotificationConditionOperatorLessThan:
+		keyNumber, err := parseNumber(parsedKey)
+		if err != nil {
+			return false, fmt.Errorf(conditionNumberErrorMessage, condition.Name, parsedKey)
+		}
+
+		valueNumber, err := parseNumber(condition.Value)
+		if err != nil {
+			return false, fmt.Errorf(conditionNumberErrorMessage, condition.Name, condition.Value)
+		}
+
+		if condition.Operator == v1alpha1.NotificationConditionOperatorGreaterThan {
+			return keyNumber > valueNumber, nil
+		}
+		return keyNumber < valueNumber, nil
+	}
+
+	return false, fmt.Errorf(conditionOperatorErrorMessage, condition.Name, condition.Operator)
+}
+
+// evaluateExpression runs the CEL expression of the condition
+func evaluateExpression(condition *v1alpha1.NotificationCondition, programs map[string]cel.Program,
+	data map[string]interface{}) (met bool, err error) {
+
+	// Programs are compiled on registration, so this only happens when the registry is being updated
+	program, found := programs[condition.Expression]
+	if !found {
+		program, err = expression.Compile(condition.Expression)
+		if err != nil {
+			return false, fmt.Errorf(conditionErrorMessage, condition.Name, err)
+		}
+	}
+
+	met, err = expression.Evaluate(program, data)
+	if err != nil {
+		return false, fmt.Errorf(conditionErrorMessage, condition.Name, err)
+	}
+
+	return met, nil
+}
+
+// parseNumber converts a number, or a Kubernetes quantity such as '500Mi', into a float
+func parseNumber(value string) (number float64, err error) {
+	value = strings.TrimSpace(value)
+
+	number, err = strconv.ParseFloat(value, 64)
+	if err == nil {
+		return number, nil
+	}
+
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return number, err
+	}
+
+	return quantity.AsApproximateFloat64(), nil
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watchers
+
+import (
+	"strings"
+	"testing"
+
+	//
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	notificationsRegistry "freepik.com/notifik/internal/registry/notifications"
+)
+
+// conditionsData simulates the data injected into conditions for a Pod event
+var conditionsData = map[string]interface{}{
+	"eventType": "MODIFIED",
+	"object": map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":      "api-7d9f",
+			"namespace": "default",
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{
+					"resources": map[string]interface{}{"limits": map[string]interface{}{"memory": "512Mi"}},
+				},
+			},
+		},
+		"status": map[string]interface{}{
+			"phase":        "Failed",
+			"restartCount": 5,
+		},
+	},
+}
+
+// compileConditions registers a Notification with the conditions, and returns it with what was compiled
+func compileConditions(t *testing.T, conditions []v1alpha1.NotificationCondition,
+	anyOf []v1alpha1.NotificationConditionGroup) (*v1alpha1.Notification, *notificationsRegistry.CompiledNotification) {
+	t.Helper()
+
+	notification := &v1alpha1.Notification{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pods"},
+		Spec: v1alpha1.NotificationSpec{
+			Watch:      v1alpha1.NotificationWatch{Version: "v1", Resource: "pods"},
+			Conditions: conditions,
+			AnyOf:      anyOf,
+		},
+	}
+
+	registry := notificationsRegistry.NewNotificationsRegistry()
+	err := registry.AddNotification("v1/pods", notification, nil)
+	if err != nil {
+		t.Fatalf("unexpected error registering the notification: %v", err)
+	}
+
+	compiled, _ := registry.GetCompiled(notification)
+	return notification, compiled
+}
+
+func TestEvaluateConditionOperators(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition v1alpha1.NotificationCondition
+		wantMet   bool
+		wantErr   string
+	}{
+		{
+			name:      "Equal is the default operator",
+			condition: v1alpha1.NotificationCondition{Key: "{{ .object.status.phase }}", Value: "Failed"},
+			wantMet:   true,
+		},
+		{
+			name: "Equal not met",
+			condition: v1alpha1.NotificationCondition{Key: "{{ .object.status.phase }}", Value: "Running",
+				Operator: v1alpha1.NotificationConditionOperatorEqual},
+		},
+		{
+			name: "NotEqual",
+			condition: v1alpha1.NotificationCondition{Key: "{{ .object.status.phase }}", Value: "Running",
+				Operator: v1alpha1.NotificationConditionOperatorNotEqual},
+			wantMet: true,
+		},
+		{
+			name: "In",
+			condition: v1alpha1.NotificationCondition{Key: "{{ .object.status.phase }}",
+				Values: []string{"Failed", "Unknown"}, Operator: v1alpha1.NotificationConditionOperatorIn},
+			wantMet: true,
+		},
+		{
+			name: "In without values",
+			condition: v1alpha1.NotificationCondition{Key: "{{ .object.status.phase }}",
+				Operator: v1alpha1.NotificationConditionOperatorIn},
+		},
+		{
+			name: "NotIn",
+			condition: v1alpha1.NotificationCondition{Key: "{{ .object.status.phase }}",
+				Values: []string{"Failed", "Unknown"}, Operator: v1alpha1.NotificationConditionOperatorNotIn},
+		},
+		{
+			name: "Exists",
+			condition: v1alpha1.NotificationCondition{Key: "{{ .object.status.phase }}",
+				Operator: v1alpha1.NotificationConditionOperatorExists},
+			wantMet: true,
+		},
+		{
+			name: "Exists for a missing key",
+			condition: v1alpha1.NotificationCondition{Key: "{{ .object.status.reason }}",
+				Operator: v1alpha1.NotificationConditionOperatorExists},
+		},
+		{
+			name: "Matches",
+			condition: v1alpha1.NotificationCondition{Key: "{{ .object.metadata.name }}", Value: "^api-[a-z0-9]+$",
+				Operator: v1alpha1.NotificationConditionOperatorMatches},
+			wantMet: true,
+		},
+		{
+			name: "Matches not met",
+			condition: v1alpha1.NotificationCondition{Key: "{{ .object.metadata.name }}", Value: "^web-",
+				Operator: v1alpha1.NotificationConditionOperatorMatches},
+		},
+		{
+			name: "GreaterThan with numbers",
+			condition: v1alpha1.NotificationCondition{Key: "{{ .object.status.restartCount }}", Value: "3",
+				Operator: v1alpha1.NotificationConditionOperatorGreaterThan},
+			wantMet: true,
+		},
+		{
+			name: "LessThan with quantities",
+			condition: v1alpha1.NotificationCondition{
+				Key:   "{{ (index .object.spec.containers 0).resources.limits.memory }}",
+				Value: "1Gi", Operator: v1alpha1.NotificationConditionOperatorLessThan},
+			wantMet: true,
+		},
+		{
+			name: "GreaterThan with a key that is not a number",
+			condition: v1alpha1.NotificationCondition{Key: "{{ .object.status.phase }}", Value: "3",
+				Operator: v1alpha1.NotificationConditionOperatorGreaterThan},
+			wantErr: "'Failed' is not a number or a quantity",
+		},
+		{
+			name: "LessThan with a value that is not a number",
+			condition: v1alpha1.NotificationCondition{Key: "{{ .object.status.restartCount }}", Value: "many",
+				Operator: v1alpha1.NotificationConditionOperatorLessThan},
+			wantErr: "'many' is not a number or a quantity",
+		},
+		{
+			name: "GreaterThan with a missing key",
+			condition: v1alpha1.NotificationCondition{Key: "{{ .object.status.reason }}", Value: "3",
+				Operator: v1alpha1.NotificationConditionOperatorGreaterThan},
+			wantErr: "is not a number or a quantity",
+		},
+		{
+			name:      "unknown operator",
+			condition: v1alpha1.NotificationCondition{Key: "{{ .object.status.phase }}", Operator: "Contains"},
+			wantErr:   "unknown operator 'Contains'",
+		},
+		{
+			name:      "key failing to render",
+			condition: v1alpha1.NotificationCondition{Key: `{{ fail "broken" }}`, Value: "Failed"},
+			wantErr:   "broken",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.condition.Name = "test"
+			notification, compiled := compileConditions(t, []v1alpha1.NotificationCondition{test.condition}, nil)
+
+			met, err := evaluateConditions(notification, compiled, conditionsData)
+			if met != test.wantMet {
+				t.Errorf("expected met %v, got %v", test.wantMet, met)
+			}
+			if test.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+				t.Errorf("expected error containing %q, got: %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestEvaluateConditionInvalidRegex(t *testing.T) {
+	condition := v1alpha1.NotificationCondition{Name: "test", Key: "{{ .object.metadata.name }}", Value: "api-(",
+		Operator: v1alpha1.NotificationConditionOperatorMatches}
+
+	// Broken regular expressions are rejected on registration
+	notification := &v1alpha1.Notification{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pods"},
+		Spec:       v1alpha1.NotificationSpec{Conditions: []v1alpha1.NotificationCondition{condition}},
+	}
+	err := notificationsRegistry.NewNotificationsRegistry().AddNotification("v1/pods", notification, nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid regular expression") {
+		t.Fatalf("expected the notification to be rejected, got: %v", err)
+	}
+
+	// They are not met when evaluated anyway
+	met, err := evaluateConditions(notification, &notificationsRegistry.CompiledNotification{}, conditionsData)
+	if met || err == nil || !strings.Contains(err.Error(), "invalid regular expression") {
+		t.Errorf("expected an invalid regular expression error, got met %v and error: %v", met, err)
+	}
+}
+
+func TestEvaluateConditionsAnyOf(t *testing.T) {
+	failed := v1alpha1.NotificationCondition{Name: "failed", Key: "{{ .object.status.phase }}", Value: "Failed"}
+	running := v1alpha1.NotificationCondition{Name: "running", Key: "{{ .object.status.phase }}", Value: "Running"}
+	restarted := v1alpha1.NotificationCondition{Name: "restarted", Key: "{{ .object.status.restartCount }}",
+		Value: "3", Operator: v1alpha1.NotificationConditionOperatorGreaterThan}
+	broken := v1alpha1.NotificationCondition{Name: "broken", Key: "{{ .object.status.phase }}", Value: "3",
+		Operator: v1alpha1.NotificationConditionOperatorGreaterThan}
+
+	tests := []struct {
+		name       string
+		conditions []v1alpha1.NotificationCondition
+		anyOf      []v1alpha1.NotificationConditionGroup
+		wantMet    bool
+		wantErr    string
+	}{
+		{
+			name:    "no conditions at all",
+			wantMet: true,
+		},
+		{
+			name:       "empty anyOf only checks the conditions",
+			conditions: []v1alpha1.NotificationCondition{failed},
+			anyOf:      []v1alpha1.NotificationConditionGroup{},
+			wantMet:    true,
+		},
+		{
+			name:       "all the conditions must be met",
+			conditions: []v1alpha1.NotificationCondition{failed, running},
+		},
+		{
+			name: "one group met is enough",
+			anyOf: []v1alpha1.NotificationConditionGroup{
+				{Conditions: []v1alpha1.NotificationCondition{running}},
+				{Conditions: []v1alpha1.NotificationCondition{failed, restarted}},
+			},
+			wantMet: true,
+		},
+		{
+			name: "groups need all their conditions",
+			anyOf: []v1alpha1.NotificationConditionGroup{
+				{Conditions: []v1alpha1.NotificationCondition{failed, running}},
+				{Conditions: []v1alpha1.NotificationCondition{running, restarted}},
+			},
+		},
+		{
+			name:       "groups are not evaluated when the conditions are not met",
+			conditions: []v1alpha1.NotificationCondition{running},
+			anyOf: []v1alpha1.NotificationConditionGroup{
+				{Conditions: []v1alpha1.NotificationCondition{failed}},
+			},
+		},
+		{
+			name:       "conditions and groups are combined",
+			conditions: []v1alpha1.NotificationCondition{restarted},
+			anyOf: []v1alpha1.NotificationConditionGroup{
+				{Conditions: []v1alpha1.NotificationCondition{running}},
+				{Conditions: []v1alpha1.NotificationCondition{failed}},
+			},
+			wantMet: true,
+		},
+		{
+			name: "groups without conditions are met",
+			anyOf: []v1alpha1.NotificationConditionGroup{
+				{Conditions: []v1alpha1.NotificationCondition{running}},
+				{Conditions: []v1alpha1.NotificationCondition{}},
+			},
+			wantMet: true,
+		},
+		{
+			name: "failing groups do not hide a group met later",
+			anyOf: []v1alpha1.NotificationConditionGroup{
+				{Conditions: []v1alpha1.NotificationCondition{broken}},
+				{Conditions: []v1alpha1.NotificationCondition{failed}},
+			},
+			wantMet: true,
+		},
+		{
+			name: "the first error is returned when no group is met",
+			anyOf: []v1alpha1.NotificationConditionGroup{
+				{Conditions: []v1alpha1.NotificationCondition{running}},
+				{Conditions: []v1alpha1.NotificationCondition{broken}},
+			},
+			wantErr: "condition 'broken'",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			notification, compiled := compileConditions(t, test.conditions, test.anyOf)
+
+			met, err := evaluateConditions(notification, compiled, conditionsData)
+			if met != test.wantMet {
+				t.Errorf("expected met %v, got %v", test.wantMet, met)
+			}
+			if test.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+				t.Errorf("expected error containing %q, got: %v", test.wantErr, err)
+			}
+		})
+	}
+}
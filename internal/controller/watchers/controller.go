@@ -18,72 +18,83 @@ package watchers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"slices"
 	"strings"
 	"time"
 
 	//
-	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
-	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/tools/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/controller"
+	"freepik.com/notifik/internal/delivery"
+	"freepik.com/notifik/internal/events"
 	"freepik.com/notifik/internal/globals"
-	"freepik.com/notifik/internal/integrations"
+	"freepik.com/notifik/internal/informers"
+	"freepik.com/notifik/internal/integrations/driver"
+	"freepik.com/notifik/internal/metrics"
 	integrationsRegistry "freepik.com/notifik/internal/registry/integrations"
 	notificationsRegistry "freepik.com/notifik/internal/registry/notifications"
+	silencesRegistry "freepik.com/notifik/internal/registry/silences"
 	sourcesRegistry "freepik.com/notifik/internal/registry/sources"
 	watchersRegistry "freepik.com/notifik/internal/registry/watchers"
-	"freepik.com/notifik/internal/template"
+	"freepik.com/notifik/internal/tenancy"
+	"freepik.com/notifik/internal/tracker"
 )
 
 const (
-	// secondsToCheckWatcherAck is the number of seconds before checking
-	// whether a watcher is started or not during watchers' reconciling process
-	secondsToCheckWatcherAck = 10 * time.Second
+	// durationToResyncWatchers is the maximum time between reconciliations of watchers.
+	// They are reconciled on demand, so this only retries the watchers that failed to start
+	durationToResyncWatchers = 10 * time.Second
 
-	// secondsToReconcileWatchersAgain is the number of seconds to wait
-	// between the moment of launching watchers, and repeating this process
-	// (avoid the spam, mate)
-	secondsToReconcileWatchersAgain = 2 * time.Second
+	// durationToStopWatchers is the maximum time to wait for a watcher to stop when it is disabled
+	durationToStopWatchers = 10 * time.Second
 
 	//
 	controllerContextFinishedMessage = "WatcherController finished by context"
 	controllerWatcherStartedMessage  = "Watcher for '%s' has been started"
+	controllerWatcherSyncedMessage   = "Watcher for '%s' has been synced"
 	controllerWatcherKilledMessage   = "Watcher for resource type '%s' killed by StopSignal"
 
 	eventConditionsTriggerIntegrationsMessage = "Object has met conditions. Integrations will be triggered"
-
-	watchedObjectParseError        = "Impossible to process watched object: %s"
-	resourceWatcherLaunchingError  = "Impossible to start watcher for resource type: %s"
-	integrationsSendMessageError   = "Impossible to send the message to some integration: %s"
-	resourceWatcherGvrParsingError = "Failed to parse GVR from resourceType. Does it look like {group}/{version}/{resource}?"
-
-	eventConditionGoTemplateError = "Go templating reported failure for object conditions: %s"
-	eventMessageGoTemplateError   = "Go templating reported failure for object message: %s"
+	eventMessagesSuppressedMessage            = "Object has met conditions, but messages were suppressed by throttling: %s"
+
+	watchedObjectParseError         = "Impossible to process watched object: %s"
+	resourceWatcherDisablingError   = "Impossible to disable watcher for resource type: %s"
+	integrationsSendMessageError    = "Impossible to queue the message for some integration: %s"
+	integrationsResolveMessageError = "Impossible to queue the resolution for some integration: %s"
+	resourceTypesExpansionError     = "Failed expanding some resource types with namespace selectors, they are skipped: %s"
+	resourceWatcherGvrParsingError  = "Failed to parse GVR from resourceType. Does it look like {group}/{version}/{resource}?"
+
+	eventConditionGoTemplateError   = "Conditions could not be evaluated for the object"
+	eventMessageGoTemplateError     = "Go templating reported failure for object message"
+	eventFingerprintGoTemplateError = "Go templating reported failure for object fingerprint. Object key is used instead"
+	eventGroupGoTemplateError       = "Go templating reported failure for object group. Default group is used instead"
 )
 
 // WatchersControllerOptions represents available options that can be passed to WatchersController on start
-type WatchersControllerOptions struct {
-	// Duration to wait until resync all the objects
-	InformerDurationToResync time.Duration
-}
+type WatchersControllerOptions struct{}
 
 type WatchersControllerDependencies struct {
 	Context *context.Context
 
 	//
-	IntegrationsRegistry  *integrationsRegistry.IntegrationsRegistry
+	DeliveryManager       *delivery.DeliveryManager
+	Informers             *informers.SharedInformers
+	EventRecorder         *events.Recorder
+	StatusTracker         *tracker.StatusTracker
 	NotificationsRegistry *notificationsRegistry.NotificationsRegistry
 	WatchersRegistry      *watchersRegistry.WatchersRegistry
 	SourcesRegistry       *sourcesRegistry.SourcesRegistry
+	SilencesRegistry      *silencesRegistry.SilencesRegistry
 }
 
 // WatchersController represents the controller that triggers parallel threads.
@@ -94,158 +105,149 @@ type WatchersController struct {
 
 	Options      WatchersControllerOptions
 	Dependencies WatchersControllerDependencies
+
+	// conditionStates remembers which objects met the conditions of Notifications triggered on transitions
+	conditionStates conditionStates
+
+	// throttlers remembers the messages sent by Notifications with throttling
+	throttlers throttlers
+
+	// digests keeps the objects batched by Notifications with grouping
+	digests digests
+
+	// syncTrigger requests the reconciliation of the watchers
+	syncTrigger controller.Trigger
 }
 
-// watchersCleanerWorker review the resource types of Notifications registry in the background.
-// It disables the watchers that are not needed and delete them from watchers registry
-// This function is intended to be used as goroutine
-func (r *WatchersController) watchersCleanerWorker() {
-	logger := log.FromContext(*r.Dependencies.Context)
-	logger.Info("Starting watchers cleaner worker")
+// eventDetails describes where an event comes from, as some Notifications are not interested in all of them
+type eventDetails struct {
+	// initialList is set for the events produced for existing objects when the watcher starts
+	initialList bool
 
-	for {
-		//
-		referentCandidates := r.Dependencies.NotificationsRegistry.GetRegisteredResourceTypes()
-		evaluableCandidates := r.Dependencies.WatchersRegistry.GetRegisteredResourceTypes()
-
-		for _, resourceType := range evaluableCandidates {
-			if !slices.Contains(referentCandidates, resourceType) {
-				err := r.Dependencies.WatchersRegistry.DisableWatcher(resourceType)
-				if err != nil {
-					logger.WithValues("resourceType", resourceType).
-						Info("Failed disabling watcher")
-				}
-			}
-		}
+	// resync is set for the events produced by periodic resyncs, where the object did not change
+	resync bool
+}
 
-		time.Sleep(5 * time.Second)
-	}
+// Sync requests the reconciliation of the watchers, so they are started or stopped as Notifications
+// or namespaces change. It never blocks, and requests made while one is pending are merged
+func (r *WatchersController) Sync() {
+	r.syncTrigger.Fire()
 }
 
 // Start launches the WatchersController and keeps it alive
-// It kills the controller on application's context death, and rerun the process when failed
+// It kills the controller on application's context death. Watchers are reconciled when requested,
+// and periodically to retry those that failed to start
 func (r *WatchersController) Start() {
 	logger := log.FromContext(*r.Dependencies.Context)
 
-	// Start cleaner for dead watchers
-	go r.watchersCleanerWorker()
-
 	// Keep your controller alive
 	for {
+		r.reconcileWatchers()
+
 		select {
 		case <-(*r.Dependencies.Context).Done():
 			logger.Info(controllerContextFinishedMessage)
 			return
-		default:
-			r.reconcileWatchers()
-			//time.Sleep(secondsToReconcileWatchersAgain)
+		case <-r.syncTrigger.C():
+		case <-time.After(durationToResyncWatchers):
 		}
 	}
 }
 
-// reconcileWatchers checks each registered resource type and triggers watchers
-// for those that are not already started.
+// reconcileWatchers launches watchers for the resource types of the Notifications that have none,
+// and disables those that are not needed anymore
 func (r *WatchersController) reconcileWatchers() {
 	logger := log.FromContext(*r.Dependencies.Context)
 
-	for _, resourceType := range r.Dependencies.NotificationsRegistry.GetRegisteredResourceTypes() {
+	// Resource types using a namespace selector are watched with one watcher per matching namespace
+	// Those that can not be expanded are skipped, keeping what is already running for them
+	resourceTypes, failedResourceTypes, err := globals.ExpandResourceTypeNames(*r.Dependencies.Context, r.Client,
+		r.Dependencies.NotificationsRegistry.GetRegisteredResourceTypes())
+	if err != nil {
+		logger.Info(fmt.Sprintf(resourceTypesExpansionError, err))
+	}
 
-		_, watcherExists := r.Dependencies.WatchersRegistry.GetWatcher(resourceType)
+	for _, resourceType := range resourceTypes {
+		if _, watcherExists := r.Dependencies.WatchersRegistry.GetWatcher(resourceType); watcherExists {
+			continue
+		}
 
-		// Avoid wasting CPU for nothing
-		if watcherExists && r.Dependencies.WatchersRegistry.IsStarted(resourceType) {
+		// Watchers are registered before launching them, so they are never launched twice
+		watcher := r.Dependencies.WatchersRegistry.RegisterWatcher(resourceType)
+		go r.watchTypeWithInformer(resourceType, watcher)
+	}
+
+	// Disabling waits for the watchers to stop, so it is done in the background
+	for _, resourceType := range r.Dependencies.WatchersRegistry.GetRegisteredResourceTypes() {
+		if slices.Contains(resourceTypes, resourceType) ||
+			slices.Contains(failedResourceTypes, globals.UnexpandedResourceTypeName(resourceType)) {
 			continue
 		}
 
-		//
-		if !watcherExists || !r.Dependencies.WatchersRegistry.IsStarted(resourceType) {
-			go r.watchTypeWithInformer(resourceType)
+		go func() {
+			ctx, cancel := context.WithTimeout(*r.Dependencies.Context, durationToStopWatchers)
+			defer cancel()
 
-			// Wait for the just started watcher to ACK itself
-			time.Sleep(secondsToCheckWatcherAck)
-			if !r.Dependencies.WatchersRegistry.IsStarted(resourceType) {
-				logger.Info(fmt.Sprintf(resourceWatcherLaunchingError, resourceType))
+			err := r.Dependencies.WatchersRegistry.DisableWatcher(ctx, resourceType)
+			if err != nil {
+				logger.WithValues("error", err).Info(fmt.Sprintf(resourceWatcherDisablingError, resourceType))
 			}
-		}
+		}()
+	}
 
-		// Reduce CPU cycles spam
-		time.Sleep(secondsToReconcileWatchersAgain)
+	// Forget the objects of Notifications that do not exist anymore
+	notificationExists := func(notificationKey string) bool {
+		namespace, name, _ := strings.Cut(notificationKey, "/")
+		_, exists := r.Dependencies.NotificationsRegistry.GetNotification(namespace, name)
+		return exists
 	}
+	r.conditionStates.Prune(notificationExists)
+	r.throttlers.Prune(notificationExists, time.Now())
+	r.digests.Prune(notificationExists)
 }
 
-// watchTypeWithInformer creates and runs a Kubernetes informer for the specified
-// resource type, and triggers processing for each event
-func (r *WatchersController) watchTypeWithInformer(resourceType watchersRegistry.ResourceTypeName) {
+// watchTypeWithInformer subscribes to the shared informer of the specified resource type,
+// and triggers processing for each event. The watcher is started once it has received all the existing objects.
+// Watchers failing to subscribe are disabled, so they are launched again on next reconciliation
+func (r *WatchersController) watchTypeWithInformer(resourceType watchersRegistry.ResourceTypeName,
+	watcher *watchersRegistry.ResourceWatcher) {
 
 	logger := log.FromContext(*r.Dependencies.Context)
-
-	watcher, watcherExists := r.Dependencies.WatchersRegistry.GetWatcher(resourceType)
-	if !watcherExists {
-		watcher = r.Dependencies.WatchersRegistry.RegisterWatcher(resourceType)
-	}
-
 	logger.Info(fmt.Sprintf(controllerWatcherStartedMessage, resourceType))
 
-	// Trigger ACK flag for watcher that is launching
-	// Hey, this informer is blocking, so ACK is only disabled if the informer becomes dead
-	_ = r.Dependencies.WatchersRegistry.SetStarted(resourceType, true)
-	defer func() {
-		_ = r.Dependencies.WatchersRegistry.SetStarted(resourceType, false)
-	}()
-
-	// Extract GVR + Namespace + Name from watched type:
-	// {group}/{version}/{resource}/{namespace}/{name}
-	GVRNN := strings.Split(resourceType, "/")
-	if len(GVRNN) != 5 {
-		logger.Info(resourceWatcherGvrParsingError)
-		return
-	}
-	resourceGVR := schema.GroupVersionResource{
-		Group:    GVRNN[0],
-		Version:  GVRNN[1],
-		Resource: GVRNN[2],
-	}
-
-	// Include the namespace when defined by the user (used as filter)
-	namespace := corev1.NamespaceAll
-	if GVRNN[3] != "" {
-		namespace = GVRNN[3]
-	}
-
-	// Include the name when defined by the user (used as filter)
-	name := GVRNN[4]
-
-	var listOptionsFunc dynamicinformer.TweakListOptionsFunc = func(options *metav1.ListOptions) {}
-	if name != "" {
-		listOptionsFunc = func(options *metav1.ListOptions) {
-			options.FieldSelector = "metadata.name=" + name
-		}
-	}
+	// ACK flag is only enabled once synced, and disabled if the watcher becomes dead.
+	// Done is closed once everything is released, so disabling the watcher waits for it
+	defer r.Dependencies.WatchersRegistry.FinishWatcher(watcher)
 
 	// Listen to stop signal to kill this watcher just in case it's needed
 	stopCh := make(chan struct{})
 
 	go func() {
-		<-watcher.StopSignal
+		select {
+		case <-watcher.StopSignal:
+		case <-(*r.Dependencies.Context).Done():
+		}
 		close(stopCh)
 		logger.Info(fmt.Sprintf(controllerWatcherKilledMessage, resourceType))
 	}()
 
-	// Define our informer TODO
-	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(globals.Application.KubeRawClient,
-		r.Options.InformerDurationToResync, namespace, listOptionsFunc)
-
-	// Create an informer. This is a special type of client-go watcher that includes
-	// mechanisms to hide disconnections, handle reconnections, and cache watched objects
-	informer := factory.ForResource(resourceGVR).Informer()
+	// Extract GVR, namespace, name and selectors from the resource type
+	parsedResourceType, err := globals.ParseResourceTypeName(resourceType)
+	if err != nil {
+		logger.Info(resourceWatcherGvrParsingError, "error", err)
+		r.Dependencies.WatchersRegistry.RemoveWatcher(resourceType, watcher)
+		return
+	}
 
-	// Register functions to handle different types of events
-	handlers := cache.ResourceEventHandlerFuncs{
+	// Register functions to handle different types of events.
+	// Detailed handlers are used to know which events come from the initial list of objects
+	handlers := cache.ResourceEventHandlerDetailedFuncs{
 
-		AddFunc: func(eventObject interface{}) {
+		AddFunc: func(eventObject interface{}, isInInitialList bool) {
 			convertedEventObject := eventObject.(*unstructured.Unstructured)
 
-			err := r.processEvent(resourceType, watch.Added, convertedEventObject.UnstructuredContent())
+			err := r.processEvent(resourceType, watch.Added, eventDetails{initialList: isInInitialList},
+				convertedEventObject.UnstructuredContent())
 			if err != nil {
 				logger.Error(err, fmt.Sprintf(watchedObjectParseError, err))
 			}
@@ -254,7 +256,12 @@ func (r *WatchersController) watchTypeWithInformer(resourceType watchersRegistry
 			convertedEventObjectOld := eventObjectOld.(*unstructured.Unstructured)
 			convertedEventObject := eventObject.(*unstructured.Unstructured)
 
-			err := r.processEvent(resourceType, watch.Modified,
+			// Resyncs deliver the same object again, so its version does not change
+			details := eventDetails{
+				resync: convertedEventObject.GetResourceVersion() == convertedEventObjectOld.GetResourceVersion(),
+			}
+
+			err := r.processEvent(resourceType, watch.Modified, details,
 				convertedEventObject.UnstructuredContent(), convertedEventObjectOld.UnstructuredContent())
 			if err != nil {
 				logger.Error(err, fmt.Sprintf(watchedObjectParseError, err))
@@ -263,28 +270,46 @@ func (r *WatchersController) watchTypeWithInformer(resourceType watchersRegistry
 		DeleteFunc: func(eventObject interface{}) {
 			convertedEventObject := eventObject.(*unstructured.Unstructured)
 
-			err := r.processEvent(resourceType, watch.Deleted, convertedEventObject.UnstructuredContent())
+			err := r.processEvent(resourceType, watch.Deleted, eventDetails{}, convertedEventObject.UnstructuredContent())
 			if err != nil {
 				logger.Error(err, fmt.Sprintf(watchedObjectParseError, err))
 			}
 		},
 	}
 
-	_, err := informer.AddEventHandler(handlers)
+	// Subscribe to the informer shared by all the resource types with the same GVR and namespace.
+	// It is filtered by the name and selectors of this resource type
+	subscription, err := r.Dependencies.Informers.Subscribe(parsedResourceType, handlers)
 	if err != nil {
-		logger.Error(err, "Error adding handling functions for events to an informer")
+		logger.Error(err, "Error subscribing to a shared informer")
+		r.Dependencies.WatchersRegistry.RemoveWatcher(resourceType, watcher)
 		return
 	}
+	defer func() {
+		_ = subscription.Unsubscribe()
+	}()
 
-	informer.Run(stopCh)
+	// The watcher is started once it has received the initial list of objects
+	if !cache.WaitForCacheSync(stopCh, subscription.HasSynced) {
+		return
+	}
+	_ = r.Dependencies.WatchersRegistry.SetStarted(resourceType, true)
+	logger.Info(fmt.Sprintf(controllerWatcherSyncedMessage, resourceType))
+
+	<-stopCh
 }
 
 // processEvent process an event coming from a watched resource type.
-// It computes templating, evaluates conditions and decides whether to send a message for a given manifest
-func (r *WatchersController) processEvent(resourceType watchersRegistry.ResourceTypeName, eventType watch.EventType, object ...map[string]interface{}) (err error) {
+// It computes templating, evaluates conditions and decides whether to send a message for a given manifest.
+// Where the event comes from is described by 'details'
+func (r *WatchersController) processEvent(resourceType watchersRegistry.ResourceTypeName, eventType watch.EventType,
+	details eventDetails, object ...map[string]interface{}) (err error) {
 	logger := log.FromContext(*r.Dependencies.Context)
 
-	notificationList := r.Dependencies.NotificationsRegistry.GetNotifications(resourceType)
+	// Watchers of namespaces matching a selector serve the Notifications using the selector
+	notificationList := r.Dependencies.NotificationsRegistry.GetNotifications(globals.UnexpandedResourceTypeName(resourceType))
+
+	metrics.WatcherEvents.WithLabelValues(resourceType, string(eventType)).Inc()
 
 	// Process only certain event types
 	if eventType != watch.Added && eventType != watch.Modified && eventType != watch.Deleted {
@@ -307,8 +332,33 @@ func (r *WatchersController) processEvent(resourceType watchersRegistry.Resource
 		templateInjectedObject["previousObject"] = object[1]
 	}
 
+	// Messages failing to be queued do not stop the rest, their errors are returned together
+	var messageErrors []error
+
 	//
 	for _, notification := range notificationList {
+		notificationKey := fmt.Sprintf("%s/%s", notification.Namespace, notification.Name)
+		// Notifications removed since the list was taken have nothing restricting them anymore
+		compiled, compiledFound := r.Dependencies.NotificationsRegistry.GetCompiled(notification)
+		if !compiledFound {
+			continue
+		}
+
+		// Deleted objects never meet the conditions again, so Integrations tracking them are told
+		// before anything can discard the event, whatever the conditions say
+		if eventType == watch.Deleted {
+			r.resolveMessages(notification, compiled, eventType, object[0], templateInjectedObject)
+		}
+
+		// Discard the events the Notification is not interested in, before doing any templating work
+		if !isEventAccepted(notification, eventType, details) {
+			continue
+		}
+
+		// Notifications restricted by tenancy only see the objects in the namespaces they are allowed to
+		if !compiled.Scope.AllowsObject(&object[0]) {
+			continue
+		}
 
 		// Time to add sources from 'extraResources'
 		templateInjectedObject["sources"] = [][]*map[string]any{}
@@ -318,59 +368,297 @@ func (r *WatchersController) processEvent(resourceType watchersRegistry.Resource
 		}
 
 		for _, resource := range notification.Spec.ExtraResources {
-			joinedResourceName := strings.Join([]string{
-				resource.Group, resource.Version, resource.Resource,
-				resource.Namespace, resource.Name,
-			}, "/")
+			joinedResourceName := globals.ResourceTypeFromExtraResource(&resource).String()
 
-			tmpResourceList := r.Dependencies.SourcesRegistry.GetResources(joinedResourceName)
+			tmpResourceList := r.getSourceResources(joinedResourceName, compiled.Scope)
 			sources = append(sources, tmpResourceList)
 		}
 		templateInjectedObject["sources"] = sources
 
-		//
-		var conditionFlags []bool
-		for _, condition := range notification.Spec.Conditions {
-			parsedKey, err := template.EvaluateTemplate(condition.Key, templateInjectedObject)
-			if err != nil {
-				logger.WithValues(
-					"notification", fmt.Sprintf("%s/%s", notification.Namespace, notification.Name),
-					"object", fmt.Sprintf("%s/%s", objectBasicData["namespace"], objectBasicData["name"]),
-					"error", err).Info(eventConditionGoTemplateError)
-				conditionFlags = append(conditionFlags, false)
-				continue
+		// Conditions that can not be evaluated are not met, so the failure is reported and the object is treated as such
+		conditionsMet, err := evaluateConditions(notification, compiled, templateInjectedObject)
+		if err != nil {
+			logger.WithValues(
+				"notification", fmt.Sprintf("%s/%s", notification.Namespace, notification.Name),
+				"object", fmt.Sprintf("%s/%s", objectBasicData["namespace"], objectBasicData["name"]),
+				"error", err).Info(eventConditionGoTemplateError)
+			metrics.TemplateErrors.WithLabelValues(notificationKey, metrics.TemplateStageCondition).Inc()
+			r.Dependencies.EventRecorder.Record(notification, object[0], events.Warning,
+				events.ReasonTemplateFailed, events.ConditionTemplateFailedMessage, err.Error())
+			r.Dependencies.StatusTracker.RecordFailure(notification, err)
+		}
+
+		// Notifications triggered on transitions only fire when the object starts meeting the conditions
+		transitioned := true
+		if notification.Spec.TriggerMode == v1alpha1.NotificationTriggerModeOnTransition {
+			objectKey := fmt.Sprintf("%s/%s", objectBasicData["namespace"], objectBasicData["name"])
+			transitioned = r.conditionStates.Transition(notificationKey, objectKey, conditionsMet)
+			if eventType == watch.Deleted {
+				r.conditionStates.Forget(notificationKey, objectKey)
 			}
-			conditionFlags = append(conditionFlags, parsedKey == condition.Value)
 		}
 
-		if slices.Contains(conditionFlags, false) {
+		if !conditionsMet {
+			metrics.ConditionEvaluations.WithLabelValues(notificationKey, metrics.ResultNotMet).Inc()
+
+			// Integrations tracking the objects that met the conditions must know they do not meet them anymore.
+			// Deleted objects were already resolved
+			if eventType != watch.Deleted {
+				r.resolveMessages(notification, compiled, eventType, object[0], templateInjectedObject)
+			}
 			continue
 		}
+		metrics.ConditionEvaluations.WithLabelValues(notificationKey, metrics.ResultMet).Inc()
 
-		parsedMessage, err := template.EvaluateTemplate(notification.Spec.Message.Data, templateInjectedObject)
-		if err != nil {
+		if !transitioned {
+			continue
+		}
+		r.Dependencies.StatusTracker.RecordTrigger(notification, object[0])
+
+		// Messages silenced during maintenance windows are not sent, nor counted by throttling
+		if r.Dependencies.SilencesRegistry.IsSilenced(notification, object[0], time.Now()) {
+			metrics.SuppressedMessages.WithLabelValues(notificationKey, metrics.SuppressionSilenced).Inc()
 			logger.WithValues(
 				"notification", fmt.Sprintf("%s/%s", notification.Namespace, notification.Name),
-				"object", fmt.Sprintf("%s/%s", objectBasicData["namespace"], objectBasicData["name"]),
-				"error", err).Info(eventMessageGoTemplateError)
+				"object", fmt.Sprintf("%s/%s", objectBasicData["namespace"], objectBasicData["name"])).
+				Info(fmt.Sprintf(eventMessagesSuppressedMessage, metrics.SuppressionSilenced))
 			continue
 		}
 
+		// Messages are throttled once for all of them, so similar events do not flood the integrations
+		templateInjectedObject["suppressed"] = 0
+		if isThrottlingEnabled(&notification.Spec.Throttling) {
+			fingerprint := r.getFingerprint(notification, compiled, object[0], templateInjectedObject)
+			allowed, suppressed, reason := r.throttlers.Allow(notificationKey, &notification.Spec.Throttling,
+				fingerprint, time.Now())
+			if !allowed {
+				metrics.SuppressedMessages.WithLabelValues(notificationKey, reason).Inc()
+				logger.WithValues(
+					"notification", fmt.Sprintf("%s/%s", notification.Namespace, notification.Name),
+					"object", fmt.Sprintf("%s/%s", objectBasicData["namespace"], objectBasicData["name"])).
+					Info(fmt.Sprintf(eventMessagesSuppressedMessage, reason))
+				continue
+			}
+			templateInjectedObject["suppressed"] = suppressed
+		}
+
 		logger.WithValues(
 			"notification", fmt.Sprintf("%s/%s", notification.Namespace, notification.Name),
 			"object", fmt.Sprintf("%s/%s", objectBasicData["namespace"], objectBasicData["name"])).
 			Info(eventConditionsTriggerIntegrationsMessage)
 
-		// Send the message through integrations
-		err = integrations.SendMessage(*r.Dependencies.Context, r.Dependencies.IntegrationsRegistry,
-			notification.Spec.Message.Integration.Name, parsedMessage)
+		// Objects of Notifications with grouping are batched, and sent later in a digest
+		if notification.Spec.Grouping != nil {
+			objectKey := fmt.Sprintf("%s/%s", objectBasicData["namespace"], objectBasicData["name"])
+			groupKey := r.getGroupKey(notification, compiled, objectKey, templateInjectedObject)
+			r.digests.Add(notification, groupKey, objectKey, maps.Clone(templateInjectedObject), r.sendDigest)
+			continue
+		}
+
+		// Every message is rendered from the same evaluation of the conditions
+		for _, message := range notification.Spec.GetMessages() {
+			err = r.sendMessage(notification, message, compiled, eventType, object[0], templateInjectedObject)
+			if err != nil {
+				messageErrors = append(messageErrors, err)
+			}
+		}
+	}
+
+	return errors.Join(messageErrors...)
+}
+
+// resolveMessages tells the Integrations of the Notification that the object does not meet its conditions anymore.
+// Only the ones tracking the object do something with it
+func (r *WatchersController) resolveMessages(notification *v1alpha1.Notification,
+	compiled *notificationsRegistry.CompiledNotification, eventType watch.EventType,
+	object map[string]interface{}, templateInjectedObject map[string]interface{}) {
+
+	for _, message := range notification.Spec.GetMessages() {
+		err := r.Dependencies.DeliveryManager.Resolve(integrationsRegistry.GetReferenceKey(&message.Integration), &driver.Message{
+			EventType:    eventType,
+			Notification: notification,
+			Target:       message,
+			Object:       object,
+			TemplateData: maps.Clone(templateInjectedObject),
+			Templates:    compiled.Templates,
+		})
 		if err != nil {
-			logger.WithValues(
+			objectBasicData, _ := globals.GetObjectBasicData(&object)
+			log.FromContext(*r.Dependencies.Context).WithValues(
 				"notification", fmt.Sprintf("%s/%s", notification.Namespace, notification.Name),
 				"object", fmt.Sprintf("%s/%s", objectBasicData["namespace"], objectBasicData["name"])).
-				Info(fmt.Sprintf(integrationsSendMessageError, err))
+				Info(fmt.Sprintf(integrationsResolveMessageError, err))
 		}
 	}
+}
+
+// getFingerprint renders the fingerprint identifying the messages of the object considered duplicated.
+// The namespace and name of the object are used when it is not defined or can not be rendered
+func (r *WatchersController) getFingerprint(notification *v1alpha1.Notification,
+	compiled *notificationsRegistry.CompiledNotification, object map[string]interface{},
+	templateInjectedObject map[string]interface{}) string {
+
+	objectBasicData, _ := globals.GetObjectBasicData(&object)
+	objectKey := fmt.Sprintf("%s/%s", objectBasicData["namespace"], objectBasicData["name"])
+
+	if notification.Spec.Throttling.Fingerprint == "" {
+		return objectKey
+	}
+
+	fingerprint, err := compiled.Templates.Evaluate(notification.Spec.Throttling.Fingerprint, templateInjectedObject)
+	if err != nil {
+		notificationKey := fmt.Sprintf("%s/%s", notification.Namespace, notification.Name)
+		log.FromContext(*r.Dependencies.Context).WithValues(
+			"notification", notificationKey,
+			"object", objectKey,
+			"error", err).Info(eventFingerprintGoTemplateError)
+		metrics.TemplateErrors.WithLabelValues(notificationKey, metrics.TemplateStageFingerprint).Inc()
+		r.Dependencies.StatusTracker.RecordFailure(notification, err)
+		return objectKey
+	}
+
+	return fingerprint
+}
+
+// getGroupKey renders the key of the group where the object is batched.
+// The default group is used when it is not defined or can not be rendered
+func (r *WatchersController) getGroupKey(notification *v1alpha1.Notification,
+	compiled *notificationsRegistry.CompiledNotification, objectKey string,
+	templateInjectedObject map[string]interface{}) string {
+
+	if notification.Spec.Grouping.GroupBy == "" {
+		return ""
+	}
+
+	groupKey, err := compiled.Templates.Evaluate(notification.Spec.Grouping.GroupBy, templateInjectedObject)
+	if err != nil {
+		notificationKey := fmt.Sprintf("%s/%s", notification.Namespace, notification.Name)
+		log.FromContext(*r.Dependencies.Context).WithValues(
+			"notification", notificationKey,
+			"object", objectKey,
+			"error", err).Info(eventGroupGoTemplateError)
+		metrics.TemplateErrors.WithLabelValues(notificationKey, metrics.TemplateStageGroup).Inc()
+		r.Dependencies.StatusTracker.RecordFailure(notification, err)
+		return ""
+	}
+
+	return groupKey
+}
+
+// sendDigest sends the messages of the Notification for a group of batched objects.
+// Templates of the messages receive the group, and the data of each object in '.objects'
+func (r *WatchersController) sendDigest(notification *v1alpha1.Notification, groupKey string,
+	objects []map[string]interface{}, count int) {
+
+	templateInjectedObject := map[string]interface{}{
+		"group":   groupKey,
+		"objects": objects,
+		"count":   count,
+	}
+
+	// Messages are delivered on behalf of the first object of the digest
+	firstObject := objects[0]
+	object, _ := firstObject["object"].(map[string]interface{})
+	eventType, _ := firstObject["eventType"].(watch.EventType)
+
+	// Digests of Notifications removed while they were batching are dropped
+	compiled, compiledFound := r.Dependencies.NotificationsRegistry.GetCompiled(notification)
+	if !compiledFound {
+		return
+	}
+	for _, message := range notification.Spec.GetMessages() {
+		_ = r.sendMessage(notification, message, compiled, eventType, object, templateInjectedObject)
+	}
+}
+
+// sendMessage renders the message of the Notification and queues it to be delivered through its Integration.
+// Failures are reported on the Notification
+func (r *WatchersController) sendMessage(notification *v1alpha1.Notification, message v1alpha1.NotificationMessage,
+	compiled *notificationsRegistry.CompiledNotification, eventType watch.EventType,
+	object map[string]interface{}, templateInjectedObject map[string]interface{}) (err error) {
+
+	notificationKey := fmt.Sprintf("%s/%s", notification.Namespace, notification.Name)
+	integrationKey := integrationsRegistry.GetReferenceKey(&message.Integration)
+	objectBasicData, _ := globals.GetObjectBasicData(&object)
+	logger := log.FromContext(*r.Dependencies.Context).WithValues(
+		"notification", notificationKey,
+		"object", fmt.Sprintf("%s/%s", objectBasicData["namespace"], objectBasicData["name"]),
+		"integration", integrationKey)
+
+	parsedMessage, err := compiled.Templates.Evaluate(message.Data, templateInjectedObject)
+	if err != nil {
+		logger.WithValues("error", err).Info(eventMessageGoTemplateError)
+		metrics.TemplateErrors.WithLabelValues(notificationKey, metrics.TemplateStageMessage).Inc()
+		r.Dependencies.EventRecorder.Record(notification, object, events.Warning,
+			events.ReasonTemplateFailed, events.MessageTemplateFailedMessage, err.Error())
+		r.Dependencies.StatusTracker.RecordFailure(notification, err)
+		return err
+	}
+
+	// Queue the message to be delivered through its integration.
+	// Template data is copied as it is modified for the next Notification while the message waits
+	err = r.Dependencies.DeliveryManager.Send(integrationKey, &driver.Message{
+		Data:         parsedMessage,
+		EventType:    eventType,
+		Notification: notification,
+		Target:       message,
+		Object:       object,
+		TemplateData: maps.Clone(templateInjectedObject),
+		Templates:    compiled.Templates,
+	})
+	if err != nil {
+		logger.Info(fmt.Sprintf(integrationsSendMessageError, err))
+		r.Dependencies.EventRecorder.Record(notification, object, events.Warning, events.ReasonDeliveryFailed,
+			events.DeliveryFailedMessage, integrationKey, err.Error())
+		r.Dependencies.StatusTracker.RecordFailure(notification, err)
+		return err
+	}
+
+	r.Dependencies.EventRecorder.Record(notification, object, events.Normal, events.ReasonTriggered,
+		events.TriggeredMessage, integrationKey)
+
+	return nil
+}
+
+// isEventAccepted checks whether the Notification evaluates events of the given type
+func isEventAccepted(notification *v1alpha1.Notification, eventType watch.EventType, details eventDetails) bool {
+	if details.initialList && notification.Spec.Watch.IgnoreInitialList {
+		return false
+	}
+
+	if details.resync && notification.Spec.Watch.IgnoreResyncs {
+		return false
+	}
+
+	if len(notification.Spec.Watch.EventTypes) == 0 {
+		return true
+	}
+
+	return slices.Contains(notification.Spec.Watch.EventTypes, v1alpha1.NotificationEventType(eventType))
+}
+
+// getSourceResources return the resources of an extra resource type allowed by the scope.
+// Those using a namespace selector are collected from the informers of all the matching namespaces
+func (r *WatchersController) getSourceResources(resourceType sourcesRegistry.ResourceTypeName,
+	scope *tenancy.Scope) []*map[string]any {
+	resources := []*map[string]any{}
+
+	parsedResourceType, err := globals.ParseResourceTypeName(resourceType)
+	if err != nil || parsedResourceType.NamespaceSelector == "" {
+		resources = r.Dependencies.SourcesRegistry.GetResources(resourceType)
+	} else {
+		for _, registeredResourceType := range r.Dependencies.SourcesRegistry.GetRegisteredResourceTypes() {
+			if globals.UnexpandedResourceTypeName(registeredResourceType) == resourceType {
+				resources = append(resources, r.Dependencies.SourcesRegistry.GetResources(registeredResourceType)...)
+			}
+		}
+	}
+
+	if scope == nil {
+		return resources
+	}
 
-	return err
+	return slices.DeleteFunc(slices.Clone(resources), func(resource *map[string]any) bool {
+		return !scope.AllowsObject(resource)
+	})
 }
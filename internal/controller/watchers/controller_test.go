@@ -0,0 +1,247 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watchers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	//
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/delivery"
+	"freepik.com/notifik/internal/globals"
+	"freepik.com/notifik/internal/integrations/webhook"
+	integrationsRegistry "freepik.com/notifik/internal/registry/integrations"
+	notificationsRegistry "freepik.com/notifik/internal/registry/notifications"
+	silencesRegistry "freepik.com/notifik/internal/registry/silences"
+)
+
+// alertmanagerServer records the alerts posted to a fake Alertmanager
+type alertmanagerServer struct {
+	mu     sync.Mutex
+	alerts webhook.AlertmanagerAlertList
+}
+
+func (s *alertmanagerServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	alerts := webhook.AlertmanagerAlertList{}
+	_ = json.NewDecoder(r.Body).Decode(&alerts)
+
+	s.mu.Lock()
+	s.alerts = append(s.alerts, alerts...)
+	s.mu.Unlock()
+}
+
+// waitAlerts waits until the provided number of alerts is posted, and returns them
+func (s *alertmanagerServer) waitAlerts(t *testing.T, count int) webhook.AlertmanagerAlertList {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		alerts := append(webhook.AlertmanagerAlertList{}, s.alerts...)
+		s.mu.Unlock()
+
+		if len(alerts) >= count {
+			return alerts
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("expected %d alerts to be posted", count)
+	return nil
+}
+
+// isAlertFiring checks whether the alert ends in the future
+func isAlertFiring(t *testing.T, alert *webhook.AlertmanagerAlert) bool {
+	t.Helper()
+
+	endsAt, err := time.Parse(time.RFC3339, alert.EndsAt)
+	if err != nil {
+		t.Fatalf("unexpected endsAt %q: %v", alert.EndsAt, err)
+	}
+	return endsAt.After(time.Now())
+}
+
+func TestProcessEventResolvesDeletedObjects(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventTypes []v1alpha1.NotificationEventType
+	}{
+		{
+			name: "deleted objects meeting the conditions are resolved on transitions",
+		},
+		{
+			name:       "deleted objects are resolved when DELETED events are not evaluated",
+			eventTypes: []v1alpha1.NotificationEventType{"ADDED", "MODIFIED"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &alertmanagerServer{}
+			httpServer := httptest.NewServer(server)
+			defer httpServer.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			integrations := integrationsRegistry.NewIntegrationsRegistry()
+			integrations.AddIntegration(&v1alpha1.Integration{
+				ObjectMeta: metav1.ObjectMeta{Name: "alertmanager"},
+				Spec: v1alpha1.IntegrationSpec{
+					Type:         "alertmanager",
+					Alertmanager: v1alpha1.IntegrationAlertmanager{Url: httpServer.URL},
+				},
+			})
+
+			notification := &v1alpha1.Notification{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "failed-pods"},
+				Spec: v1alpha1.NotificationSpec{
+					Watch: v1alpha1.NotificationWatch{
+						Version:    "v1",
+						Resource:   "pods",
+						EventTypes: test.eventTypes,
+					},
+					Conditions: []v1alpha1.NotificationCondition{{
+						Name:  "failed",
+						Key:   "{{ .object.status.phase }}",
+						Value: "Failed",
+					}},
+					Message: &v1alpha1.NotificationMessage{
+						Integration: v1alpha1.NotificationIntegration{Name: "alertmanager"},
+						Data:        "Pod failed",
+					},
+					TriggerMode: v1alpha1.NotificationTriggerModeOnTransition,
+				},
+			}
+			resourceType := globals.ResourceTypeFromWatch(&notification.Spec.Watch).String()
+
+			notifications := notificationsRegistry.NewNotificationsRegistry()
+			err := notifications.AddNotification(resourceType, notification, nil)
+			if err != nil {
+				t.Fatalf("unexpected error registering the notification: %v", err)
+			}
+
+			r := &WatchersController{
+				Dependencies: WatchersControllerDependencies{
+					Context: &ctx,
+					DeliveryManager: delivery.NewDeliveryManager(delivery.DeliveryManagerDependencies{
+						Context:               &ctx,
+						IntegrationsRegistry:  integrations,
+						NotificationsRegistry: notifications,
+					}),
+					NotificationsRegistry: notifications,
+					SilencesRegistry:      silencesRegistry.NewSilencesRegistry(),
+				},
+			}
+
+			pod := map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata": map[string]interface{}{
+					"namespace": "default",
+					"name":      "example",
+					"uid":       "1234",
+				},
+				"status": map[string]interface{}{"phase": "Failed"},
+			}
+
+			err = r.processEvent(resourceType, watch.Added, eventDetails{}, pod)
+			if err != nil {
+				t.Fatalf("unexpected error processing the ADDED event: %v", err)
+			}
+			alerts := server.waitAlerts(t, 1)
+			if !isAlertFiring(t, &alerts[0]) {
+				t.Fatalf("expected the alert to be firing, got endsAt %q", alerts[0].EndsAt)
+			}
+
+			// The object still meets the conditions when it is deleted, so it does not transition
+			err = r.processEvent(resourceType, watch.Deleted, eventDetails{}, pod)
+			if err != nil {
+				t.Fatalf("unexpected error processing the DELETED event: %v", err)
+			}
+			alerts = server.waitAlerts(t, 2)
+			if isAlertFiring(t, &alerts[1]) {
+				t.Fatalf("expected the alert to be resolved, got endsAt %q", alerts[1].EndsAt)
+			}
+		})
+	}
+}
+
+func TestProcessEventJoinsMessageErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// None of the Integrations exist, so every message fails to be queued
+	notification := &v1alpha1.Notification{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pods"},
+		Spec: v1alpha1.NotificationSpec{
+			Watch: v1alpha1.NotificationWatch{Version: "v1", Resource: "pods"},
+			Messages: []v1alpha1.NotificationMessage{
+				{Integration: v1alpha1.NotificationIntegration{Name: "first"}, Data: "Pod added"},
+				{Integration: v1alpha1.NotificationIntegration{Name: "second"}, Data: "Pod added"},
+			},
+		},
+	}
+	resourceType := globals.ResourceTypeFromWatch(&notification.Spec.Watch).String()
+
+	notifications := notificationsRegistry.NewNotificationsRegistry()
+	err := notifications.AddNotification(resourceType, notification, nil)
+	if err != nil {
+		t.Fatalf("unexpected error registering the notification: %v", err)
+	}
+
+	r := &WatchersController{
+		Dependencies: WatchersControllerDependencies{
+			Context: &ctx,
+			DeliveryManager: delivery.NewDeliveryManager(delivery.DeliveryManagerDependencies{
+				Context:               &ctx,
+				IntegrationsRegistry:  integrationsRegistry.NewIntegrationsRegistry(),
+				NotificationsRegistry: notifications,
+			}),
+			NotificationsRegistry: notifications,
+			SilencesRegistry:      silencesRegistry.NewSilencesRegistry(),
+		},
+	}
+
+	pod := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"namespace": "default", "name": "example"},
+	}
+
+	err = r.processEvent(resourceType, watch.Added, eventDetails{}, pod)
+	if err == nil {
+		t.Fatalf("expected an error processing the event")
+	}
+	for _, integrationName := range []string{"first", "second"} {
+		if !strings.Contains(err.Error(), fmt.Sprintf(delivery.IntegrationNotFoundErrorMessage, integrationName)) {
+			t.Errorf("expected the error of integration '%s', got: %v", integrationName, err)
+		}
+	}
+}
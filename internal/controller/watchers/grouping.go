@@ -0,0 +1,209 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watchers
+
+import (
+	"sync"
+	"time"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+)
+
+const (
+	// Defaults applied when 'spec.grouping' of the Notification does not set them
+	defaultGroupWait     = 30 * time.Second
+	defaultGroupInterval = 5 * time.Minute
+
+	// maxDigestObjects is the maximum number of objects kept in a digest.
+	// Objects coming once it is full are counted, but not kept, so memory is bounded on noisy sources
+	maxDigestObjects = 1000
+)
+
+// digest represents the objects batched for a group of a Notification, waiting to be sent together
+type digest struct {
+	notification *v1alpha1.Notification
+
+	// objects are the template data of the events batched, indexed by object in 'objectIndexes',
+	// so only the last event of each object is kept
+	objects       []map[string]interface{}
+	objectIndexes map[string]int
+	count         int
+
+	timer    groupingTimer
+	lastSent time.Time
+}
+
+// digestFlushFunc is called with the objects of a group when its digest is due
+type digestFlushFunc func(notification *v1alpha1.Notification, groupKey string, objects []map[string]interface{}, count int)
+
+// digests keeps, per Notification and group, the objects waiting to be sent in a digest.
+// This memory is not persisted, so the objects waiting are lost on restarts
+type digests struct {
+	mu     sync.Mutex
+	groups map[string]map[string]*digest
+
+	// clock is the source of time of the digests, the system one when not set
+	clock groupingClock
+}
+
+// groupingClock represents the source of time of the digests, so tests can move it instead of waiting
+type groupingClock interface {
+	Now() time.Time
+	AfterFunc(wait time.Duration, f func()) groupingTimer
+}
+
+// groupingTimer is the part of a time.Timer used by the digests
+type groupingTimer interface {
+	Stop() bool
+}
+
+// systemClock is the groupingClock backed by the time package
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(wait time.Duration, f func()) groupingTimer {
+	return time.AfterFunc(wait, f)
+}
+
+// getClock return the clock of the digests, or the system one when not set
+func (d *digests) getClock() groupingClock {
+	if d.clock == nil {
+		return systemClock{}
+	}
+	return d.clock
+}
+
+// getGroupWait return the time to wait before sending the first digest of a group
+func getGroupWait(grouping *v1alpha1.NotificationGrouping) time.Duration {
+	if grouping.GroupWait == nil {
+		return defaultGroupWait
+	}
+	return grouping.GroupWait.Duration
+}
+
+// getGroupInterval return the time to wait between digests of a group
+func getGroupInterval(grouping *v1alpha1.NotificationGrouping) time.Duration {
+	if grouping.GroupInterval == nil {
+		return defaultGroupInterval
+	}
+	return grouping.GroupInterval.Duration
+}
+
+// Add batches the event of an object into the digest of its group. The digest is flushed by calling 'flush'
+// once 'groupWait' passes for new groups, or once 'groupInterval' passes since the previous digest of the group
+func (d *digests) Add(notification *v1alpha1.Notification, groupKey, objectKey string,
+	templateData map[string]interface{}, flush digestFlushFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.groups == nil {
+		d.groups = make(map[string]map[string]*digest)
+	}
+
+	notificationKey := notification.Namespace + "/" + notification.Name
+	notificationGroups, found := d.groups[notificationKey]
+	if !found {
+		notificationGroups = make(map[string]*digest)
+		d.groups[notificationKey] = notificationGroups
+	}
+
+	group, found := notificationGroups[groupKey]
+	if !found {
+		group = &digest{}
+		notificationGroups[groupKey] = group
+	}
+
+	// Latest version of the Notification is used to render the digest
+	group.notification = notification
+	group.count++
+
+	if group.objectIndexes == nil {
+		group.objectIndexes = make(map[string]int)
+	}
+	if index, found := group.objectIndexes[objectKey]; found {
+		group.objects[index] = templateData
+		group.count--
+	} else if len(group.objects) < maxDigestObjects {
+		group.objectIndexes[objectKey] = len(group.objects)
+		group.objects = append(group.objects, templateData)
+	}
+
+	if group.timer != nil {
+		return
+	}
+
+	wait := getGroupWait(notification.Spec.Grouping)
+	if !group.lastSent.IsZero() {
+		wait = max(0, group.lastSent.Add(getGroupInterval(notification.Spec.Grouping)).Sub(d.getClock().Now()))
+	}
+
+	group.timer = d.getClock().AfterFunc(wait, func() {
+		d.flush(notificationKey, groupKey, group, flush)
+	})
+}
+
+// flush takes the objects of the digest out, and calls 'flush' with them
+func (d *digests) flush(notificationKey, groupKey string, group *digest, flush digestFlushFunc) {
+	d.mu.Lock()
+
+	// The group may have been pruned while waiting
+	if d.groups[notificationKey][groupKey] != group {
+		d.mu.Unlock()
+		return
+	}
+
+	notification, objects, count := group.notification, group.objects, group.count
+	group.objects = nil
+	group.objectIndexes = nil
+	group.count = 0
+	group.timer = nil
+	group.lastSent = d.getClock().Now()
+	d.mu.Unlock()
+
+	if len(objects) == 0 {
+		return
+	}
+	flush(notification, groupKey, objects, count)
+}
+
+// Prune deletes the groups of the Notifications for which 'keep' returns false,
+// and those with nothing waiting whose interval already passed
+func (d *digests) Prune(keep func(notificationKey string) bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.getClock().Now()
+	for notificationKey, notificationGroups := range d.groups {
+		for groupKey, group := range notificationGroups {
+			expired := group.timer == nil && now.Sub(group.lastSent) > getGroupInterval(group.notification.Spec.Grouping)
+			if !keep(notificationKey) || expired {
+				if group.timer != nil {
+					group.timer.Stop()
+				}
+				delete(notificationGroups, groupKey)
+			}
+		}
+
+		if len(notificationGroups) == 0 {
+			delete(d.groups, notificationKey)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watchers
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	//
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+)
+
+// fakeClock is a groupingClock whose time only moves when the tests advance it.
+// Timers due are fired synchronously by 'Advance', so the tests never sleep
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// fakeTimer is a timer of the fakeClock
+type fakeTimer struct {
+	clock   *fakeClock
+	at      time.Time
+	f       func()
+	stopped bool
+	fired   bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(wait time.Duration, f func()) groupingTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	timer := &fakeTimer{clock: c, at: c.now.Add(wait), f: f}
+	c.timers = append(c.timers, timer)
+	return timer
+}
+
+// Advance moves the time forward, and fires the timers due in the order they were due
+func (c *fakeClock) Advance(duration time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(duration)
+
+	var due []*fakeTimer
+	pending := c.timers[:0]
+	for _, timer := range c.timers {
+		switch {
+		case timer.stopped:
+		case timer.at.After(c.now):
+			pending = append(pending, timer)
+		default:
+			timer.fired = true
+			due = append(due, timer)
+		}
+	}
+	c.timers = pending
+	c.mu.Unlock()
+
+	sort.SliceStable(due, func(i, j int) bool { return due[i].at.Before(due[j].at) })
+	for _, timer := range due {
+		timer.f()
+	}
+}
+
+// Pending return the number of timers waiting to be fired
+func (c *fakeClock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pending := 0
+	for _, timer := range c.timers {
+		if !timer.stopped {
+			pending++
+		}
+	}
+	return pending
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	active := !t.stopped && !t.fired
+	t.stopped = true
+	return active
+}
+
+// digestFlush represents a call to the digestFlushFunc
+type digestFlush struct {
+	groupKey string
+	objects  []map[string]interface{}
+	count    int
+}
+
+// digestRecorder records the digests flushed
+type digestRecorder struct {
+	flushes []digestFlush
+}
+
+func (r *digestRecorder) flush(_ *v1alpha1.Notification, groupKey string, objects []map[string]interface{}, count int) {
+	r.flushes = append(r.flushes, digestFlush{groupKey: groupKey, objects: objects, count: count})
+}
+
+// newGroupingNotification return a Notification grouping its events with the given wait and interval
+func newGroupingNotification(name string, groupWait, groupInterval time.Duration) *v1alpha1.Notification {
+	notification := &v1alpha1.Notification{}
+	notification.Namespace = "default"
+	notification.Name = name
+	notification.Spec.Grouping = &v1alpha1.NotificationGrouping{
+		GroupWait:     &metav1.Duration{Duration: groupWait},
+		GroupInterval: &metav1.Duration{Duration: groupInterval},
+	}
+	return notification
+}
+
+func TestDigestsWaitForGroupWait(t *testing.T) {
+	clock := newFakeClock()
+	d := &digests{clock: clock}
+	recorder := &digestRecorder{}
+	notification := newGroupingNotification("grouped", 30*time.Second, 5*time.Minute)
+
+	d.Add(notification, "group", "pod-a", map[string]interface{}{"version": 1}, recorder.flush)
+	clock.Advance(10 * time.Second)
+	d.Add(notification, "group", "pod-b", map[string]interface{}{"version": 1}, recorder.flush)
+	d.Add(notification, "group", "pod-a", map[string]interface{}{"version": 2}, recorder.flush)
+
+	clock.Advance(19 * time.Second)
+	if len(recorder.flushes) != 0 {
+		t.Fatalf("digest flushed before the group wait: %v", recorder.flushes)
+	}
+
+	clock.Advance(time.Second)
+	if len(recorder.flushes) != 1 {
+		t.Fatalf("expected 1 digest once the group wait passed, got %d", len(recorder.flushes))
+	}
+
+	// Only the last event of each object is kept, and it does not count twice
+	flushed := recorder.flushes[0]
+	if flushed.groupKey != "group" || flushed.count != 2 || len(flushed.objects) != 2 {
+		t.Fatalf("unexpected digest: %+v", flushed)
+	}
+	if flushed.objects[0]["version"] != 2 {
+		t.Errorf("expected the last event of 'pod-a', got %v", flushed.objects[0])
+	}
+}
+
+func TestDigestsWaitForGroupInterval(t *testing.T) {
+	clock := newFakeClock()
+	d := &digests{clock: clock}
+	recorder := &digestRecorder{}
+	notification := newGroupingNotification("grouped", 30*time.Second, 5*time.Minute)
+
+	d.Add(notification, "group", "pod-a", map[string]interface{}{}, recorder.flush)
+	clock.Advance(30 * time.Second)
+
+	// Events coming after a digest wait for the interval since it was sent, not for the group wait
+	clock.Advance(time.Minute)
+	d.Add(notification, "group", "pod-b", map[string]interface{}{}, recorder.flush)
+	clock.Advance(3*time.Minute + 59*time.Second)
+	if len(recorder.flushes) != 1 {
+		t.Fatalf("second digest flushed before the group interval: %v", recorder.flushes)
+	}
+	clock.Advance(time.Second)
+	if len(recorder.flushes) != 2 {
+		t.Fatalf("expected 2 digests once the group interval passed, got %d", len(recorder.flushes))
+	}
+
+	// Events coming once the interval already passed are sent right away
+	clock.Advance(10 * time.Minute)
+	d.Add(notification, "group", "pod-c", map[string]interface{}{}, recorder.flush)
+	clock.Advance(0)
+	if len(recorder.flushes) != 3 {
+		t.Fatalf("expected 3 digests right after an event past the group interval, got %d", len(recorder.flushes))
+	}
+}
+
+func TestDigestsKeepGroupsApart(t *testing.T) {
+	clock := newFakeClock()
+	d := &digests{clock: clock}
+	recorder := &digestRecorder{}
+	fast := newGroupingNotification("fast", 10*time.Second, time.Minute)
+	slow := newGroupingNotification("slow", time.Minute, time.Minute)
+
+	d.Add(fast, "a", "pod", map[string]interface{}{}, recorder.flush)
+	d.Add(fast, "b", "pod", map[string]interface{}{}, recorder.flush)
+	d.Add(slow, "a", "pod", map[string]interface{}{}, recorder.flush)
+
+	clock.Advance(10 * time.Second)
+	if len(recorder.flushes) != 2 {
+		t.Fatalf("expected the 2 groups of the fast Notification, got %v", recorder.flushes)
+	}
+
+	clock.Advance(50 * time.Second)
+	if len(recorder.flushes) != 3 {
+		t.Fatalf("expected the group of the slow Notification too, got %v", recorder.flushes)
+	}
+}
+
+func TestDigestsBoundObjects(t *testing.T) {
+	clock := newFakeClock()
+	d := &digests{clock: clock}
+	recorder := &digestRecorder{}
+	notification := newGroupingNotification("grouped", time.Second, time.Minute)
+
+	for i := 0; i < maxDigestObjects+10; i++ {
+		d.Add(notification, "group", fmt.Sprintf("pod-%d", i), map[string]interface{}{}, recorder.flush)
+	}
+	clock.Advance(time.Second)
+
+	if len(recorder.flushes) != 1 {
+		t.Fatalf("expected 1 digest, got %d", len(recorder.flushes))
+	}
+	if got := len(recorder.flushes[0].objects); got != maxDigestObjects {
+		t.Errorf("expected %d objects kept, got %d", maxDigestObjects, got)
+	}
+	if got := recorder.flushes[0].count; got != maxDigestObjects+10 {
+		t.Errorf("expected every object counted, got %d", got)
+	}
+}
+
+func TestDigestsPrune(t *testing.T) {
+	clock := newFakeClock()
+	d := &digests{clock: clock}
+	recorder := &digestRecorder{}
+	removed := newGroupingNotification("removed", 30*time.Second, time.Minute)
+	idle := newGroupingNotification("idle", time.Second, time.Minute)
+	waiting := newGroupingNotification("waiting", time.Second, time.Minute)
+
+	d.Add(idle, "group", "pod", map[string]interface{}{}, recorder.flush)
+	clock.Advance(time.Second)
+	d.Add(removed, "group", "pod", map[string]interface{}{}, recorder.flush)
+	d.Add(waiting, "group", "pod", map[string]interface{}{}, recorder.flush)
+	clock.Advance(time.Second)
+	d.Add(waiting, "group", "pod", map[string]interface{}{}, recorder.flush)
+
+	keep := func(notificationKey string) bool {
+		return notificationKey != "default/removed"
+	}
+
+	// Groups whose interval did not pass yet are kept to respect it
+	d.Prune(keep)
+	if _, found := d.groups["default/removed"]; found {
+		t.Errorf("groups of removed Notifications must be pruned")
+	}
+	if _, found := d.groups["default/idle"]; !found {
+		t.Errorf("idle groups must be kept until their interval passes")
+	}
+	if clock.Pending() != 1 {
+		t.Errorf("expected only the timer of the waiting group, got %d timers", clock.Pending())
+	}
+
+	clock.Advance(time.Minute)
+	d.Prune(keep)
+	if _, found := d.groups["default/idle"]; found {
+		t.Errorf("idle groups must be pruned once their interval passes")
+	}
+	if _, found := d.groups["default/waiting"]; !found {
+		t.Errorf("groups flushed less than an interval ago must be kept")
+	}
+
+	// The removed Notification never reached its integrations
+	for _, flushed := range recorder.flushes {
+		if flushed.count == 0 {
+			t.Errorf("empty digests must not be flushed: %+v", flushed)
+		}
+	}
+	if len(recorder.flushes) != 3 {
+		t.Errorf("expected the digests of the idle and waiting groups only, got %d", len(recorder.flushes))
+	}
+}
+
+func TestDigestsIgnoreTimersOfPrunedGroups(t *testing.T) {
+	clock := newFakeClock()
+	d := &digests{clock: clock}
+	recorder := &digestRecorder{}
+	notification := newGroupingNotification("grouped", 30*time.Second, time.Minute)
+
+	d.Add(notification, "group", "pod", map[string]interface{}{}, recorder.flush)
+	notificationKey := notification.Namespace + "/" + notification.Name
+	group := d.groups[notificationKey]["group"]
+
+	d.Prune(func(string) bool { return false })
+
+	// A timer firing while the group is pruned must not flush it
+	d.flush(notificationKey, "group", group, recorder.flush)
+	clock.Advance(time.Minute)
+	if len(recorder.flushes) != 0 {
+		t.Errorf("pruned groups must not be flushed, got %v", recorder.flushes)
+	}
+}
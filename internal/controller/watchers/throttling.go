@@ -0,0 +1,166 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watchers
+
+import (
+	"sync"
+	"time"
+
+	//
+	"golang.org/x/time/rate"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/metrics"
+)
+
+const (
+	// defaultThrottlingInterval is the interval of 'maxMessages' when the Notification does not set it
+	defaultThrottlingInterval = 1 * time.Minute
+)
+
+// throttlers keeps, per Notification, the rate limiter of its messages and when each fingerprint was sent.
+// This memory is not persisted, so messages are sent again after restarts
+type throttlers struct {
+	mu            sync.Mutex
+	notifications map[string]*notificationThrottler
+}
+
+// notificationThrottler represents the throttling state of a Notification
+type notificationThrottler struct {
+	// throttling is the configuration the state was built for. The state is built again when it changes
+	throttling v1alpha1.NotificationThrottling
+
+	limiter      *rate.Limiter
+	fingerprints map[string]*fingerprintState
+}
+
+// fingerprintState represents what happened with the messages of a fingerprint
+type fingerprintState struct {
+	lastSeen   time.Time
+	lastSent   time.Time
+	suppressed int
+}
+
+// isThrottlingEnabled checks whether the throttling of a Notification limits anything
+func isThrottlingEnabled(throttling *v1alpha1.NotificationThrottling) bool {
+	return throttling.DeduplicationWindow != nil || throttling.MaxMessages > 0
+}
+
+// getDeduplicationWindow return the deduplication window of the throttling, zero when not set
+func getDeduplicationWindow(throttling *v1alpha1.NotificationThrottling) time.Duration {
+	if throttling.DeduplicationWindow == nil {
+		return 0
+	}
+	return throttling.DeduplicationWindow.Duration
+}
+
+// getThrottlingInterval return the interval of the throttling, or the default one
+func getThrottlingInterval(throttling *v1alpha1.NotificationThrottling) time.Duration {
+	if throttling.Interval == nil || throttling.Interval.Duration <= 0 {
+		return defaultThrottlingInterval
+	}
+	return throttling.Interval.Duration
+}
+
+// newNotificationThrottler builds the throttling state for the configuration of a Notification
+func newNotificationThrottler(throttling *v1alpha1.NotificationThrottling) *notificationThrottler {
+	limit := rate.Inf
+	burst := throttling.Burst
+	if throttling.MaxMessages > 0 {
+		limit = rate.Limit(float64(throttling.MaxMessages) / getThrottlingInterval(throttling).Seconds())
+		if burst <= 0 {
+			burst = throttling.MaxMessages
+		}
+	}
+
+	return &notificationThrottler{
+		throttling:   *throttling.DeepCopy(),
+		limiter:      rate.NewLimiter(limit, burst),
+		fingerprints: make(map[string]*fingerprintState),
+	}
+}
+
+// Allow decides whether a message with the fingerprint can be sent by the Notification.
+// When it can, it returns how many messages with the same fingerprint were suppressed since the last one sent.
+// When it can not, it returns the reason
+func (t *throttlers) Allow(notificationKey string, throttling *v1alpha1.NotificationThrottling, fingerprint string,
+	now time.Time) (allowed bool, suppressed int, reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.notifications == nil {
+		t.notifications = make(map[string]*notificationThrottler)
+	}
+
+	throttler, found := t.notifications[notificationKey]
+	if !found || !equalThrottling(&throttler.throttling, throttling) {
+		throttler = newNotificationThrottler(throttling)
+		t.notifications[notificationKey] = throttler
+	}
+
+	state, found := throttler.fingerprints[fingerprint]
+	if !found {
+		state = &fingerprintState{}
+		throttler.fingerprints[fingerprint] = state
+	}
+	state.lastSeen = now
+
+	window := getDeduplicationWindow(throttling)
+	if window > 0 && !state.lastSent.IsZero() && now.Sub(state.lastSent) < window {
+		state.suppressed++
+		return false, 0, metrics.SuppressionDuplicate
+	}
+
+	if !throttler.limiter.AllowN(now, 1) {
+		state.suppressed++
+		return false, 0, metrics.SuppressionRateLimited
+	}
+
+	suppressed = state.suppressed
+	state.suppressed = 0
+	state.lastSent = now
+
+	return true, suppressed, ""
+}
+
+// Prune deletes the states of the Notifications for which 'keep' returns false,
+// and the fingerprints whose messages are not throttled anymore
+func (t *throttlers) Prune(keep func(notificationKey string) bool, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for notificationKey, throttler := range t.notifications {
+		if !keep(notificationKey) {
+			delete(t.notifications, notificationKey)
+			continue
+		}
+
+		expiration := max(getDeduplicationWindow(&throttler.throttling), getThrottlingInterval(&throttler.throttling))
+		for fingerprint, state := range throttler.fingerprints {
+			if now.Sub(state.lastSeen) > expiration {
+				delete(throttler.fingerprints, fingerprint)
+			}
+		}
+	}
+}
+
+// equalThrottling checks whether two throttling configurations are the same
+func equalThrottling(a, b *v1alpha1.NotificationThrottling) bool {
+	return a.Fingerprint == b.Fingerprint && a.MaxMessages == b.MaxMessages && a.Burst == b.Burst &&
+		getDeduplicationWindow(a) == getDeduplicationWindow(b) && getThrottlingInterval(a) == getThrottlingInterval(b)
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watchers
+
+import (
+	"testing"
+	"time"
+
+	//
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/metrics"
+)
+
+// throttlingStart is the time the throttling tests start at. Time is moved by the tests, so they never sleep
+var throttlingStart = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// throttlingAttempt represents a message offered to the throttlers, and what is expected from it
+type throttlingAttempt struct {
+	after          time.Duration
+	fingerprint    string
+	wantAllowed    bool
+	wantSuppressed int
+	wantReason     string
+}
+
+func TestThrottlersAllow(t *testing.T) {
+	tests := []struct {
+		name       string
+		throttling v1alpha1.NotificationThrottling
+		attempts   []throttlingAttempt
+	}{
+		{
+			name: "duplicates are suppressed during the window, and counted on the next message",
+			throttling: v1alpha1.NotificationThrottling{
+				DeduplicationWindow: &metav1.Duration{Duration: 5 * time.Minute},
+			},
+			attempts: []throttlingAttempt{
+				{after: 0, fingerprint: "a", wantAllowed: true},
+				{after: time.Minute, fingerprint: "a", wantReason: metrics.SuppressionDuplicate},
+				{after: 4*time.Minute + 59*time.Second, fingerprint: "a", wantReason: metrics.SuppressionDuplicate},
+				{after: 5 * time.Minute, fingerprint: "a", wantAllowed: true, wantSuppressed: 2},
+				{after: 6 * time.Minute, fingerprint: "a", wantReason: metrics.SuppressionDuplicate},
+			},
+		},
+		{
+			name: "fingerprints are deduplicated independently",
+			throttling: v1alpha1.NotificationThrottling{
+				DeduplicationWindow: &metav1.Duration{Duration: 5 * time.Minute},
+			},
+			attempts: []throttlingAttempt{
+				{after: 0, fingerprint: "a", wantAllowed: true},
+				{after: time.Minute, fingerprint: "b", wantAllowed: true},
+				{after: 2 * time.Minute, fingerprint: "a", wantReason: metrics.SuppressionDuplicate},
+			},
+		},
+		{
+			name: "messages over the rate are suppressed until tokens are refilled",
+			throttling: v1alpha1.NotificationThrottling{
+				MaxMessages: 2,
+				Interval:    &metav1.Duration{Duration: time.Minute},
+			},
+			attempts: []throttlingAttempt{
+				{after: 0, fingerprint: "a", wantAllowed: true},
+				{after: 0, fingerprint: "b", wantAllowed: true},
+				{after: 0, fingerprint: "c", wantReason: metrics.SuppressionRateLimited},
+				{after: 29 * time.Second, fingerprint: "c", wantReason: metrics.SuppressionRateLimited},
+				{after: 30 * time.Second, fingerprint: "c", wantAllowed: true, wantSuppressed: 2},
+				{after: 30 * time.Second, fingerprint: "d", wantReason: metrics.SuppressionRateLimited},
+			},
+		},
+		{
+			name: "burst allows more messages at once than the rate",
+			throttling: v1alpha1.NotificationThrottling{
+				MaxMessages: 1,
+				Interval:    &metav1.Duration{Duration: time.Minute},
+				Burst:       3,
+			},
+			attempts: []throttlingAttempt{
+				{after: 0, fingerprint: "a", wantAllowed: true},
+				{after: 0, fingerprint: "b", wantAllowed: true},
+				{after: 0, fingerprint: "c", wantAllowed: true},
+				{after: 0, fingerprint: "d", wantReason: metrics.SuppressionRateLimited},
+				{after: time.Minute, fingerprint: "d", wantAllowed: true, wantSuppressed: 1},
+			},
+		},
+		{
+			name: "the default interval is one minute",
+			throttling: v1alpha1.NotificationThrottling{
+				MaxMessages: 1,
+			},
+			attempts: []throttlingAttempt{
+				{after: 0, fingerprint: "a", wantAllowed: true},
+				{after: 59 * time.Second, fingerprint: "a", wantReason: metrics.SuppressionRateLimited},
+				{after: time.Minute, fingerprint: "a", wantAllowed: true, wantSuppressed: 1},
+			},
+		},
+		{
+			name: "duplicates do not consume the rate",
+			throttling: v1alpha1.NotificationThrottling{
+				DeduplicationWindow: &metav1.Duration{Duration: 5 * time.Minute},
+				MaxMessages:         1,
+				Interval:            &metav1.Duration{Duration: time.Minute},
+			},
+			attempts: []throttlingAttempt{
+				{after: 0, fingerprint: "a", wantAllowed: true},
+				{after: time.Minute, fingerprint: "a", wantReason: metrics.SuppressionDuplicate},
+				{after: time.Minute, fingerprint: "b", wantAllowed: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			throttlers := &throttlers{}
+
+			for index, attempt := range test.attempts {
+				allowed, suppressed, reason := throttlers.Allow("default/pods", &test.throttling,
+					attempt.fingerprint, throttlingStart.Add(attempt.after))
+
+				if allowed != attempt.wantAllowed || suppressed != attempt.wantSuppressed || reason != attempt.wantReason {
+					t.Errorf("attempt %d: expected (%v, %d, %q), got (%v, %d, %q)", index,
+						attempt.wantAllowed, attempt.wantSuppressed, attempt.wantReason, allowed, suppressed, reason)
+				}
+			}
+		})
+	}
+}
+
+func TestThrottlersAllowResetsOnConfigurationChanges(t *testing.T) {
+	throttlers := &throttlers{}
+	throttling := &v1alpha1.NotificationThrottling{
+		DeduplicationWindow: &metav1.Duration{Duration: 5 * time.Minute},
+	}
+
+	allowed, _, _ := throttlers.Allow("default/pods", throttling, "a", throttlingStart)
+	if !allowed {
+		t.Fatalf("expected the first message to be allowed")
+	}
+
+	// A different window starts over, as the state was built for the previous configuration
+	changedThrottling := &v1alpha1.NotificationThrottling{
+		DeduplicationWindow: &metav1.Duration{Duration: 10 * time.Minute},
+	}
+	allowed, _, _ = throttlers.Allow("default/pods", changedThrottling, "a", throttlingStart.Add(time.Minute))
+	if !allowed {
+		t.Errorf("expected the message to be allowed after changing the configuration")
+	}
+
+	// Notifications do not share their state
+	allowed, _, _ = throttlers.Allow("default/other-pods", throttling, "a", throttlingStart.Add(time.Minute))
+	if !allowed {
+		t.Errorf("expected the message of another notification to be allowed")
+	}
+}
+
+func TestThrottlersPrune(t *testing.T) {
+	throttlers := &throttlers{}
+	throttling := &v1alpha1.NotificationThrottling{
+		DeduplicationWindow: &metav1.Duration{Duration: 5 * time.Minute},
+	}
+
+	throttlers.Allow("default/pods", throttling, "old", throttlingStart)
+	throttlers.Allow("default/pods", throttling, "recent", throttlingStart.Add(4*time.Minute))
+	throttlers.Allow("default/removed", throttling, "old", throttlingStart)
+
+	throttlers.Prune(func(notificationKey string) bool {
+		return notificationKey != "default/removed"
+	}, throttlingStart.Add(5*time.Minute+time.Second))
+
+	if _, found := throttlers.notifications["default/removed"]; found {
+		t.Errorf("expected the state of the removed notification to be pruned")
+	}
+
+	fingerprints := throttlers.notifications["default/pods"].fingerprints
+	if _, found := fingerprints["old"]; found {
+		t.Errorf("expected the expired fingerprint to be pruned")
+	}
+	if _, found := fingerprints["recent"]; !found {
+		t.Errorf("expected the recent fingerprint to be kept")
+	}
+}
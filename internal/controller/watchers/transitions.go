@@ -0,0 +1,75 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watchers
+
+import (
+	"sync"
+)
+
+// conditionStates remembers, per Notification and object, whether the conditions were met on the last evaluation.
+// It is used to fire Notifications only when their conditions transition from not met to met.
+// This memory is not persisted, so all the objects are considered not meeting the conditions on restarts
+type conditionStates struct {
+	mu     sync.Mutex
+	states map[string]map[string]bool
+}
+
+// Transition stores whether the object meets the conditions of the Notification,
+// and returns whether it did not meet them before
+func (c *conditionStates) Transition(notificationKey, objectKey string, met bool) (transitioned bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.states == nil {
+		c.states = make(map[string]map[string]bool)
+	}
+
+	objectStates, found := c.states[notificationKey]
+	if !found {
+		objectStates = make(map[string]bool)
+		c.states[notificationKey] = objectStates
+	}
+
+	previouslyMet := objectStates[objectKey]
+	if met {
+		objectStates[objectKey] = true
+	} else {
+		delete(objectStates, objectKey)
+	}
+
+	return met && !previouslyMet
+}
+
+// Forget deletes the state of the object for the Notification. It is used when the object is deleted
+func (c *conditionStates) Forget(notificationKey, objectKey string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.states[notificationKey], objectKey)
+}
+
+// Prune deletes the states of the Notifications for which 'keep' returns false
+func (c *conditionStates) Prune(keep func(notificationKey string) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for notificationKey := range c.states {
+		if !keep(notificationKey) {
+			delete(c.states, notificationKey)
+		}
+	}
+}
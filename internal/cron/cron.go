@@ -0,0 +1,172 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	//
+	FieldsNumberError = "cron expression must have 5 fields (minute hour day-of-month month day-of-week), found %d"
+	FieldParseError   = "field '%s' of cron expression is not valid: %s"
+	ValueRangeError   = "value %d out of range [%d, %d]"
+)
+
+// field represents the limits of a field of cron expressions
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day-of-month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day-of-week", min: 0, max: 7},
+}
+
+// Schedule represents a parsed cron expression with the standard 5 fields.
+// Each field admits '*', values, ranges 'a-b', steps '*/n' or 'a-b/n', and lists of them separated by commas
+type Schedule struct {
+	// values holds a bit per allowed value of each field
+	values [5]uint64
+
+	// Days are matched by day-of-month or by day-of-week when both are restricted, as done by cron
+	dayOfMonthRestricted bool
+	dayOfWeekRestricted  bool
+}
+
+// Parse parses a cron expression with the standard 5 fields
+func Parse(expression string) (schedule *Schedule, err error) {
+	expressionFields := strings.Fields(expression)
+	if len(expressionFields) != len(fields) {
+		return nil, fmt.Errorf(FieldsNumberError, len(expressionFields))
+	}
+
+	schedule = &Schedule{}
+	for fieldIndex, expressionField := range expressionFields {
+		schedule.values[fieldIndex], err = parseField(expressionField, fields[fieldIndex])
+		if err != nil {
+			return nil, fmt.Errorf(FieldParseError, fields[fieldIndex].name, err.Error())
+		}
+	}
+
+	// Sunday can be written as 0 or 7
+	if schedule.values[4]&(1<<7) != 0 {
+		schedule.values[4] |= 1
+	}
+
+	schedule.dayOfMonthRestricted = !strings.HasPrefix(expressionFields[2], "*")
+	schedule.dayOfWeekRestricted = !strings.HasPrefix(expressionFields[4], "*")
+
+	return schedule, nil
+}
+
+// parseField return the bits of the values allowed by a field of a cron expression
+func parseField(expressionField string, f field) (values uint64, err error) {
+	for _, part := range strings.Split(expressionField, ",") {
+		rangeExpression, stepExpression, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			step, err = strconv.Atoi(stepExpression)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("step '%s' is not a positive number", stepExpression)
+			}
+		}
+
+		start, end := f.min, f.max
+		if rangeExpression != "*" {
+			startExpression, endExpression, isRange := strings.Cut(rangeExpression, "-")
+
+			start, err = parseValue(startExpression, f)
+			if err != nil {
+				return 0, err
+			}
+
+			end = start
+			if isRange {
+				end, err = parseValue(endExpression, f)
+				if err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				end = f.max
+			}
+
+			if start > end {
+				return 0, fmt.Errorf("range '%s' is reversed", rangeExpression)
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			values |= 1 << value
+		}
+	}
+
+	return values, nil
+}
+
+// parseValue parses a number of a field of a cron expression, checking its limits
+func parseValue(valueExpression string, f field) (value int, err error) {
+	value, err = strconv.Atoi(valueExpression)
+	if err != nil {
+		return 0, fmt.Errorf("value '%s' is not a number", valueExpression)
+	}
+
+	if value < f.min || value > f.max {
+		return 0, fmt.Errorf(ValueRangeError, value, f.min, f.max)
+	}
+
+	return value, nil
+}
+
+// Matches checks whether the minute of the time matches the schedule
+func (s *Schedule) Matches(t time.Time) bool {
+	if s.values[0]&(1<<t.Minute()) == 0 || s.values[1]&(1<<t.Hour()) == 0 || s.values[3]&(1<<int(t.Month())) == 0 {
+		return false
+	}
+
+	dayOfMonthMatches := s.values[2]&(1<<t.Day()) != 0
+	dayOfWeekMatches := s.values[4]&(1<<int(t.Weekday())) != 0
+
+	if s.dayOfMonthRestricted && s.dayOfWeekRestricted {
+		return dayOfMonthMatches || dayOfWeekMatches
+	}
+	return dayOfMonthMatches && dayOfWeekMatches
+}
+
+// Last return the last time matching the schedule, looking back from 't' up to 'lookback'.
+// It returns false when the schedule does not match any time in that period
+func (s *Schedule) Last(t time.Time, lookback time.Duration) (last time.Time, found bool) {
+	current := t.Truncate(time.Minute)
+	oldest := t.Add(-lookback)
+
+	for !current.Before(oldest) {
+		if s.Matches(current) {
+			return current, true
+		}
+		current = current.Add(-time.Minute)
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleMatches(t *testing.T) {
+	// 2024-06-01 is a Saturday
+	saturday := time.Date(2024, time.June, 1, 2, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expression string
+		time       time.Time
+		expected   bool
+	}{
+		{"* * * * *", saturday, true},
+		{"30 2 * * *", saturday, true},
+		{"*/15 * * * *", saturday, true},
+		{"*/20 * * * *", saturday, false},
+		{"0-29 * * * *", saturday, false},
+		{"0 2 * * 6", saturday.Add(-30 * time.Minute), true},
+		{"* * * * 1-5", saturday, false},
+		{"* * * * 0,7", saturday.Add(24 * time.Hour), true},
+		{"* * 15 * *", saturday, false},
+
+		// Restricting both days matches any of them
+		{"* * 15 * 6", saturday, true},
+	}
+
+	for _, test := range tests {
+		schedule, err := Parse(test.expression)
+		if err != nil {
+			t.Fatalf("%s: unexpected error parsing the expression: %s", test.expression, err)
+		}
+		if result := schedule.Matches(test.time); result != test.expected {
+			t.Errorf("%s: expected %t for %s, got %t", test.expression, test.expected, test.time, result)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := Parse(expression); err == nil {
+			t.Errorf("%s: expected an error parsing the expression", expression)
+		}
+	}
+}
+
+func TestScheduleLast(t *testing.T) {
+	schedule, err := Parse("0 2 * * 6")
+	if err != nil {
+		t.Fatalf("unexpected error parsing the expression: %s", err)
+	}
+
+	now := time.Date(2024, time.June, 1, 5, 15, 30, 0, time.UTC)
+	last, found := schedule.Last(now, 4*time.Hour)
+	if !found || !last.Equal(time.Date(2024, time.June, 1, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected last time at 02:00, got %s (found: %t)", last, found)
+	}
+
+	if _, found = schedule.Last(now, 3*time.Hour); found {
+		t.Errorf("expected no time matching in the last 3 hours")
+	}
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadletter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	//
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// configMapNamePrefix is the prefix of the ConfigMaps keeping the messages of each Integration
+	configMapNamePrefix = "notifik-dead-letters-"
+
+	// IntegrationLabel is the label of the ConfigMaps pointing to the Integration of their messages.
+	// Names longer than allowed in label values are shortened, so the full name is kept in IntegrationAnnotation
+	IntegrationLabel      = "notifik.freepik.com/integration"
+	IntegrationAnnotation = "notifik.freepik.com/integration"
+
+	// DefaultMaxBytes is the size allowed for the messages of each ConfigMap.
+	// It leaves room for the metadata under the limit of 1MiB that Kubernetes sets for each object
+	DefaultMaxBytes = 900 * 1024
+
+	// truncatedHashLength is the number of characters of the hash appended to names and label values too long
+	truncatedHashLength = 10
+
+	//
+	EntryEncodingErrorMessage = "error encoding dead letter: %s"
+	EntryDecodingErrorMessage = "error decoding dead letter '%s': %s"
+	EntryTooLargeErrorMessage = "dead letter takes %d bytes, more than the %d bytes allowed"
+)
+
+// ConfigMapStore keeps the messages of each Integration in its own ConfigMap.
+// Only the newest messages are kept when the maximum number or size is reached, as ConfigMaps are limited in size
+type ConfigMapStore struct {
+	mu sync.Mutex
+
+	// Reader is used to read ConfigMaps directly from the API, so they are not cached in memory
+	Client client.Client
+	Reader client.Reader
+
+	Namespace  string
+	MaxEntries int
+	MaxBytes   int
+}
+
+func NewConfigMapStore(kubeClient client.Client, reader client.Reader, namespace string, maxEntries int) *ConfigMapStore {
+	return &ConfigMapStore{
+		Client:     kubeClient,
+		Reader:     reader,
+		Namespace:  namespace,
+		MaxEntries: maxEntries,
+		MaxBytes:   DefaultMaxBytes,
+	}
+}
+
+// Add keeps a message in the ConfigMap of its Integration, creating it when needed
+func (s *ConfigMapStore) Add(ctx context.Context, entry *Entry) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry.Id == "" {
+		entry.Id = strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+
+	entryBytes, err := s.encodeEntry(entry)
+	if err != nil {
+		return err
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, exists, err := s.getConfigMap(ctx, entry.Integration)
+		if err != nil {
+			return err
+		}
+
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[entry.Id] = string(entryBytes)
+
+		// Forget the oldest messages. Identifiers are timestamps, so sorting them is enough
+		if s.MaxEntries > 0 && len(configMap.Data) > s.MaxEntries {
+			entryIds := getSortedKeys(configMap.Data)
+			for _, entryId := range entryIds[:len(entryIds)-s.MaxEntries] {
+				delete(configMap.Data, entryId)
+			}
+		}
+		if s.MaxBytes > 0 {
+			dataBytes := getDataBytes(configMap.Data)
+			for _, entryId := range getSortedKeys(configMap.Data) {
+				if dataBytes <= s.MaxBytes {
+					break
+				}
+				dataBytes -= len(entryId) + len(configMap.Data[entryId])
+				delete(configMap.Data, entryId)
+			}
+		}
+
+		if !exists {
+			return s.Client.Create(ctx, configMap)
+		}
+		return s.Client.Update(ctx, configMap)
+	})
+}
+
+// encodeEntry return the entry encoded to be stored. The content of the object is reduced to its identity when
+// the entry does not fit in the allowed size, keeping the rendered message, which is enough to replay it
+func (s *ConfigMapStore) encodeEntry(entry *Entry) (entryBytes []byte, err error) {
+	entryBytes, err = json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf(EntryEncodingErrorMessage, err)
+	}
+
+	if s.MaxBytes <= 0 || len(entry.Id)+len(entryBytes) <= s.MaxBytes {
+		return entryBytes, nil
+	}
+
+	reducedEntry := *entry
+	reducedEntry.ObjectData = getReducedObject(entry.ObjectData)
+	entryBytes, err = json.Marshal(reducedEntry)
+	if err != nil {
+		return nil, fmt.Errorf(EntryEncodingErrorMessage, err)
+	}
+
+	if len(entry.Id)+len(entryBytes) > s.MaxBytes {
+		return nil, fmt.Errorf(EntryTooLargeErrorMessage, len(entry.Id)+len(entryBytes), s.MaxBytes)
+	}
+	return entryBytes, nil
+}
+
+// List return the messages kept in the ConfigMap of the Integration
+func (s *ConfigMapStore) List(ctx context.Context, integrationName string) (entries []*Entry, err error) {
+	configMap, _, err := s.getConfigMap(ctx, integrationName)
+	if err != nil {
+		return entries, err
+	}
+
+	for _, entryId := range getSortedKeys(configMap.Data) {
+		entry := &Entry{}
+		err = json.Unmarshal([]byte(configMap.Data[entryId]), entry)
+		if err != nil {
+			return entries, fmt.Errorf(EntryDecodingErrorMessage, entryId, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Remove deletes the messages from the ConfigMap of the Integration, and the ConfigMap itself when it is empty
+func (s *ConfigMapStore) Remove(ctx context.Context, integrationName string, ids []string) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, exists, err := s.getConfigMap(ctx, integrationName)
+		if err != nil || !exists {
+			return err
+		}
+
+		for _, entryId := range ids {
+			delete(configMap.Data, entryId)
+		}
+
+		if len(configMap.Data) == 0 {
+			return client.IgnoreNotFound(s.Client.Delete(ctx, configMap,
+				client.Preconditions{ResourceVersion: &configMap.ResourceVersion}))
+		}
+		return s.Client.Update(ctx, configMap)
+	})
+}
+
+// getConfigMap return the ConfigMap of the Integration, or an empty one ready to be created when it does not exist
+func (s *ConfigMapStore) getConfigMap(ctx context.Context, integrationName string) (configMap *corev1.ConfigMap, exists bool, err error) {
+	// Keys of ClusterIntegrations contain their kind, which is not valid inside names and labels
+	sanitizedName := strings.ToLower(strings.ReplaceAll(integrationName, "/", "."))
+
+	configMapName := truncateWithHash(configMapNamePrefix+sanitizedName, validation.DNS1123SubdomainMaxLength)
+
+	configMap = &corev1.ConfigMap{}
+	err = s.Reader.Get(ctx, types.NamespacedName{
+		Namespace: s.Namespace,
+		Name:      configMapName,
+	}, configMap)
+
+	if err == nil {
+		return configMap, true, nil
+	}
+
+	if !apierrors.IsNotFound(err) {
+		return configMap, false, err
+	}
+
+	configMap = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace,
+			Name:      configMapName,
+			Labels: map[string]string{
+				IntegrationLabel: truncateWithHash(sanitizedName, validation.LabelValueMaxLength),
+			},
+			Annotations: map[string]string{
+				IntegrationAnnotation: integrationName,
+			},
+		},
+	}
+	return configMap, false, nil
+}
+
+// getReducedObject return the type and the metadata identifying the object, as drivers need them
+// to recognize the objects of replayed messages, such as the UID used by Alertmanager to index alerts
+func getReducedObject(objectData map[string]interface{}) map[string]interface{} {
+	if objectData == nil {
+		return nil
+	}
+	object := &unstructured.Unstructured{Object: objectData}
+
+	reducedObject := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	reducedObject.SetAPIVersion(object.GetAPIVersion())
+	reducedObject.SetKind(object.GetKind())
+	reducedObject.SetNamespace(object.GetNamespace())
+	reducedObject.SetName(object.GetName())
+	reducedObject.SetUID(object.GetUID())
+
+	return reducedObject.Object
+}
+
+// truncateWithHash return the value fitting in the provided length, for names and label values.
+// Longer values are truncated, and the hash of the whole value is appended so different values do not end the same.
+// Separators are trimmed before the hash, as names and label values must not have them next to each other
+func truncateWithHash(value string, maxLength int) string {
+	if len(value) <= maxLength {
+		return value
+	}
+
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(value)))[:truncatedHashLength]
+	return strings.TrimRight(value[:maxLength-truncatedHashLength-1], ".-") + "-" + hash
+}
+
+// getDataBytes return the size taken by the keys and the values of the map
+func getDataBytes(data map[string]string) (size int) {
+	for key, value := range data {
+		size += len(key) + len(value)
+	}
+	return size
+}
+
+// getSortedKeys return the keys of the map sorted from the oldest identifier to the newest
+func getSortedKeys(data map[string]string) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+
+	// Identifiers are numbers, so shorter ones are always older
+	slices.SortFunc(keys, func(a, b string) int {
+		if len(a) != len(b) {
+			return len(a) - len(b)
+		}
+		return strings.Compare(a, b)
+	})
+
+	return keys
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadletter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	//
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfigMapStoreAddMaxBytes(t *testing.T) {
+	largeObject := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "example", "uid": "1234"},
+		"data":     strings.Repeat("x", 400),
+	}
+
+	tests := []struct {
+		name       string
+		maxBytes   int
+		entries    []*Entry
+		wantIds    []string
+		wantObject map[string]bool
+		wantErr    bool
+	}{
+		{
+			name:     "oldest entries are dropped when the size is exceeded",
+			maxBytes: 1500,
+			entries: []*Entry{
+				{Id: "1", Data: "first", ObjectData: largeObject},
+				{Id: "2", Data: "second", ObjectData: largeObject},
+				{Id: "3", Data: "third", ObjectData: largeObject},
+			},
+			wantIds:    []string{"2", "3"},
+			wantObject: map[string]bool{"2": true, "3": true},
+		},
+		{
+			name:     "object content is reduced to its identity when the entry alone does not fit",
+			maxBytes: 350,
+			entries: []*Entry{
+				{Id: "1", Data: "first", ObjectData: largeObject},
+			},
+			wantIds:    []string{"1"},
+			wantObject: map[string]bool{"1": false},
+		},
+		{
+			name:     "entries not fitting without the object are rejected",
+			maxBytes: 100,
+			entries: []*Entry{
+				{Id: "1", Data: strings.Repeat("x", 200)},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			kubeClient := fake.NewClientBuilder().Build()
+			store := NewConfigMapStore(kubeClient, kubeClient, "default", 0)
+			store.MaxBytes = test.maxBytes
+
+			var err error
+			for _, entry := range test.entries {
+				entry.Integration = "webhook-sender"
+				err = store.Add(ctx, entry)
+			}
+			if (err != nil) != test.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if test.wantErr {
+				return
+			}
+
+			entries, err := store.List(ctx, "webhook-sender")
+			if err != nil {
+				t.Fatalf("unexpected error listing: %v", err)
+			}
+
+			gotIds := []string{}
+			for _, entry := range entries {
+				gotIds = append(gotIds, entry.Id)
+				_, contentKept := entry.ObjectData["data"]
+				if contentKept != test.wantObject[entry.Id] {
+					t.Errorf("entry %s: object content kept is %t, want %t", entry.Id, contentKept, test.wantObject[entry.Id])
+				}
+				if uid := (&unstructured.Unstructured{Object: entry.ObjectData}).GetUID(); uid != "1234" {
+					t.Errorf("entry %s: got object uid %q, want %q", entry.Id, uid, "1234")
+				}
+			}
+			if strings.Join(gotIds, ",") != strings.Join(test.wantIds, ",") {
+				t.Errorf("got entries %v, want %v", gotIds, test.wantIds)
+			}
+		})
+	}
+}
+
+func TestConfigMapStoreAddLongIntegrationName(t *testing.T) {
+	tests := []struct {
+		name            string
+		integrationName string
+	}{
+		{
+			name:            "label value too long",
+			integrationName: "ClusterIntegration/" + strings.Repeat("a", 80),
+		},
+		{
+			name:            "name too long",
+			integrationName: "ClusterIntegration/" + strings.Repeat("a", 253),
+		},
+		{
+			name: "name too long with separators where it is truncated",
+			integrationName: "ClusterIntegration/" + strings.Repeat("a", 201) + "." +
+				strings.Repeat("b", 40),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			kubeClient := fake.NewClientBuilder().Build()
+			store := NewConfigMapStore(kubeClient, kubeClient, "default", 0)
+
+			err := store.Add(ctx, &Entry{Id: "1", Integration: test.integrationName, Data: "first"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			configMap, exists, err := store.getConfigMap(ctx, test.integrationName)
+			if err != nil || !exists {
+				t.Fatalf("expected the ConfigMap to exist: %v", err)
+			}
+
+			if errs := validation.IsDNS1123Subdomain(configMap.Name); len(errs) > 0 {
+				t.Errorf("name %q is not valid: %v", configMap.Name, errs)
+			}
+			labelValue := configMap.Labels[IntegrationLabel]
+			if errs := validation.IsValidLabelValue(labelValue); len(errs) > 0 {
+				t.Errorf("label value %q is not valid: %v", labelValue, errs)
+			}
+			if configMap.Annotations[IntegrationAnnotation] != test.integrationName {
+				t.Errorf("got annotation %q, want %q", configMap.Annotations[IntegrationAnnotation], test.integrationName)
+			}
+
+			// Integrations with the same truncated name do not share the ConfigMap
+			otherConfigMap, _, err := store.getConfigMap(ctx, test.integrationName+"c")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if otherConfigMap.Name == configMap.Name {
+				t.Errorf("expected different names for different integrations, got %q", configMap.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadletter
+
+import (
+	"context"
+	"time"
+
+	//
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// Store represents a place where the messages that could not be delivered are kept until they are replayed
+type Store interface {
+	// Add keeps a message that could not be delivered
+	Add(ctx context.Context, entry *Entry) error
+
+	// List return the messages kept for an Integration, from the oldest to the newest
+	List(ctx context.Context, integrationName string) ([]*Entry, error)
+
+	// Remove forgets the messages of an Integration with the provided identifiers
+	Remove(ctx context.Context, integrationName string, ids []string) error
+}
+
+// NotificationReference identifies the Notification that triggered a message
+type NotificationReference struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// ObjectReference identifies the watched object that triggered a message
+type ObjectReference struct {
+	ApiVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Uid        string `json:"uid,omitempty"`
+}
+
+// Entry represents a message that could not be delivered to an Integration
+type Entry struct {
+	Id          string `json:"id"`
+	Integration string `json:"integration"`
+
+	Notification NotificationReference `json:"notification"`
+	Object       ObjectReference       `json:"object"`
+
+	// Resolution is true when the message was telling the Integration that the object
+	// does not meet the conditions anymore
+	Resolution bool `json:"resolution,omitempty"`
+
+	// Data is the message already rendered
+	Data      string          `json:"data,omitempty"`
+	EventType watch.EventType `json:"eventType"`
+
+	// ObjectData is the content of the watched object, needed by drivers to replay the message
+	ObjectData map[string]interface{} `json:"objectData,omitempty"`
+
+	//
+	Error     string    `json:"error"`
+	Attempts  int       `json:"attempts"`
+	Timestamp time.Time `json:"timestamp"`
+}
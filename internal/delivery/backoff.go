@@ -0,0 +1,64 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delivery
+
+import (
+	"math/rand/v2"
+	"time"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+)
+
+// getMaxAttempts return the number of attempts to deliver a message to the Integration
+func getMaxAttempts(params *v1alpha1.IntegrationDelivery) int {
+	if params.MaxAttempts > 0 {
+		return params.MaxAttempts
+	}
+	return DefaultMaxAttempts
+}
+
+// getQueueSize return the number of messages that can wait in the queue of the Integration
+func getQueueSize(params *v1alpha1.IntegrationDelivery) int {
+	if params.QueueSize > 0 {
+		return params.QueueSize
+	}
+	return DefaultQueueSize
+}
+
+// getBackoff return the time to wait before the next attempt. It grows exponentially with the attempts
+// already done, up to the maximum configured, and half of it is random to spread retries in time
+func getBackoff(params *v1alpha1.IntegrationDelivery, attempt int) time.Duration {
+	initialBackoff := DefaultInitialBackoff
+	if params.InitialBackoff != nil && params.InitialBackoff.Duration > 0 {
+		initialBackoff = params.InitialBackoff.Duration
+	}
+
+	maxBackoff := DefaultMaxBackoff
+	if params.MaxBackoff != nil && params.MaxBackoff.Duration > 0 {
+		maxBackoff = params.MaxBackoff.Duration
+	}
+
+	backoff := initialBackoff
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, maxBackoff)
+
+	halfBackoff := backoff / 2
+	return halfBackoff + rand.N(backoff-halfBackoff+1)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delivery
+
+import (
+	"testing"
+	"time"
+
+	//
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+)
+
+// backoffSamples is the number of times each backoff is computed, to check the bounds of its random part
+const backoffSamples = 200
+
+func TestGetBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  v1alpha1.IntegrationDelivery
+		attempt int
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{
+			name:    "first retry waits the default initial backoff",
+			attempt: 1,
+			wantMin: DefaultInitialBackoff / 2,
+			wantMax: DefaultInitialBackoff,
+		},
+		{
+			name:    "backoff doubles with each attempt",
+			attempt: 3,
+			wantMin: 2 * DefaultInitialBackoff,
+			wantMax: 4 * DefaultInitialBackoff,
+		},
+		{
+			name:    "backoff is capped by the default maximum",
+			attempt: 10,
+			wantMin: DefaultMaxBackoff / 2,
+			wantMax: DefaultMaxBackoff,
+		},
+		{
+			name:    "backoff does not overflow with many attempts",
+			attempt: 1000,
+			wantMin: DefaultMaxBackoff / 2,
+			wantMax: DefaultMaxBackoff,
+		},
+		{
+			name: "configured backoffs are used",
+			params: v1alpha1.IntegrationDelivery{
+				InitialBackoff: &metav1.Duration{Duration: 2 * time.Second},
+				MaxBackoff:     &metav1.Duration{Duration: 30 * time.Second},
+			},
+			attempt: 2,
+			wantMin: 2 * time.Second,
+			wantMax: 4 * time.Second,
+		},
+		{
+			name: "backoff is capped by the configured maximum",
+			params: v1alpha1.IntegrationDelivery{
+				InitialBackoff: &metav1.Duration{Duration: 2 * time.Second},
+				MaxBackoff:     &metav1.Duration{Duration: 5 * time.Second},
+			},
+			attempt: 3,
+			wantMin: 2500 * time.Millisecond,
+			wantMax: 5 * time.Second,
+		},
+		{
+			name: "initial backoff bigger than the maximum is capped",
+			params: v1alpha1.IntegrationDelivery{
+				InitialBackoff: &metav1.Duration{Duration: time.Minute},
+				MaxBackoff:     &metav1.Duration{Duration: 10 * time.Second},
+			},
+			attempt: 1,
+			wantMin: 5 * time.Second,
+			wantMax: 10 * time.Second,
+		},
+		{
+			name: "zero durations fall back to the defaults",
+			params: v1alpha1.IntegrationDelivery{
+				InitialBackoff: &metav1.Duration{},
+				MaxBackoff:     &metav1.Duration{},
+			},
+			attempt: 2,
+			wantMin: DefaultInitialBackoff,
+			wantMax: 2 * DefaultInitialBackoff,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := map[time.Duration]bool{}
+			for i := 0; i < backoffSamples; i++ {
+				backoff := getBackoff(&tt.params, tt.attempt)
+				if backoff < tt.wantMin || backoff > tt.wantMax {
+					t.Fatalf("backoff %s out of [%s, %s]", backoff, tt.wantMin, tt.wantMax)
+				}
+				seen[backoff] = true
+			}
+
+			// Half of the backoff is random, so retries of several messages are spread in time
+			if len(seen) < 2 {
+				t.Errorf("expected random backoffs, got always %v", seen)
+			}
+		})
+	}
+}
+
+func TestGetMaxAttempts(t *testing.T) {
+	if got := getMaxAttempts(&v1alpha1.IntegrationDelivery{}); got != DefaultMaxAttempts {
+		t.Errorf("expected the default attempts %d, got %d", DefaultMaxAttempts, got)
+	}
+	if got := getMaxAttempts(&v1alpha1.IntegrationDelivery{MaxAttempts: 5}); got != 5 {
+		t.Errorf("expected the configured attempts 5, got %d", got)
+	}
+}
+
+func TestGetQueueSize(t *testing.T) {
+	if got := getQueueSize(&v1alpha1.IntegrationDelivery{}); got != DefaultQueueSize {
+		t.Errorf("expected the default size %d, got %d", DefaultQueueSize, got)
+	}
+	if got := getQueueSize(&v1alpha1.IntegrationDelivery{QueueSize: 10}); got != 10 {
+		t.Errorf("expected the configured size 10, got %d", got)
+	}
+}
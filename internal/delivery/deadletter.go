@@ -0,0 +1,167 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delivery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	//
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/deadletter"
+	"freepik.com/notifik/internal/integrations/driver"
+	integrationsRegistry "freepik.com/notifik/internal/registry/integrations"
+)
+
+const (
+	DeadLetterStoreDisabledErrorMessage = "dead letter store is disabled"
+
+	deadLetterStoredMessage     = "Message stored as dead letter for integration '%s'"
+	deadLetterStoreErrorMessage = "Impossible to store the dead letter for integration '%s': %s"
+)
+
+// storeDeadLetter keeps a discarded message in the dead letter store, so it can be replayed later
+func (m *DeliveryManager) storeDeadLetter(integrationName string, j *job, attempts int, deliveryErr error) {
+	if m.Dependencies.DeadLetterStore == nil {
+		return
+	}
+
+	ctx := *m.Dependencies.Context
+	logger := log.FromContext(ctx)
+
+	// Managed fields are only noise for the receivers, and they use a lot of space
+	object := &unstructured.Unstructured{Object: j.message.Object}
+	objectData := object.DeepCopy()
+	objectData.SetManagedFields(nil)
+
+	entry := &deadletter.Entry{
+		Integration: integrationName,
+		Notification: deadletter.NotificationReference{
+			Namespace: j.message.Notification.Namespace,
+			Name:      j.message.Notification.Name,
+		},
+		Object: deadletter.ObjectReference{
+			ApiVersion: object.GetAPIVersion(),
+			Kind:       object.GetKind(),
+			Namespace:  object.GetNamespace(),
+			Name:       object.GetName(),
+			Uid:        string(object.GetUID()),
+		},
+		Resolution: j.jobType == jobTypeResolve,
+		Data:       j.message.Data,
+		EventType:  j.message.EventType,
+		ObjectData: objectData.Object,
+		Error:      deliveryErr.Error(),
+		Attempts:   attempts,
+		Timestamp:  time.Now().UTC(),
+	}
+
+	err := m.Dependencies.DeadLetterStore.Add(ctx, entry)
+	if err != nil {
+		logger.Info(fmt.Sprintf(deadLetterStoreErrorMessage, integrationName, err))
+		return
+	}
+	logger.Info(fmt.Sprintf(deadLetterStoredMessage, integrationName))
+}
+
+// Replay queues again the messages kept in the dead letter store for the Integration.
+// Messages are removed from the store once queued, so the ones failing again are stored as new ones
+func (m *DeliveryManager) Replay(ctx context.Context, integrationName string) (replayed int, err error) {
+	if m.Dependencies.DeadLetterStore == nil {
+		return 0, errors.New(DeadLetterStoreDisabledErrorMessage)
+	}
+
+	integration, integrationFound := m.Dependencies.IntegrationsRegistry.GetIntegration(integrationName)
+	if !integrationFound {
+		return 0, fmt.Errorf(IntegrationNotFoundErrorMessage, integrationName)
+	}
+
+	entries, err := m.Dependencies.DeadLetterStore.List(ctx, integrationName)
+	if err != nil {
+		return 0, err
+	}
+
+	queuedIds := []string{}
+	for _, entry := range entries {
+		j := &job{
+			jobType: jobTypeSend,
+			message: m.getDeadLetterMessage(integrationName, entry),
+		}
+		if entry.Resolution {
+			j.jobType = jobTypeResolve
+		}
+
+		// Stop when the queue is full. Remaining messages are kept for the next replay
+		err = m.enqueue(integrationName, getQueueSize(&integration.Spec.Delivery), j)
+		if err != nil {
+			break
+		}
+		queuedIds = append(queuedIds, entry.Id)
+	}
+
+	removeErr := m.Dependencies.DeadLetterStore.Remove(ctx, integrationName, queuedIds)
+	if err == nil {
+		err = removeErr
+	}
+
+	return len(queuedIds), err
+}
+
+// getDeadLetterMessage builds the message to replay a dead letter of the Integration.
+// Template data only contains the event type and the object, as the rest is not stored
+func (m *DeliveryManager) getDeadLetterMessage(integrationName string, entry *deadletter.Entry) *driver.Message {
+	notification, notificationFound := m.Dependencies.NotificationsRegistry.GetNotification(
+		entry.Notification.Namespace, entry.Notification.Name)
+
+	// Messages are already rendered, so they can be replayed even when the Notification is gone
+	if !notificationFound {
+		notification = &v1alpha1.Notification{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: entry.Notification.Namespace,
+				Name:      entry.Notification.Name,
+			},
+		}
+	}
+
+	// Options for the Integration are taken from the message of the Notification sent through it, if any
+	target := v1alpha1.NotificationMessage{}
+	for _, message := range notification.Spec.GetMessages() {
+		if integrationsRegistry.GetReferenceKey(&message.Integration) == integrationName {
+			target = message
+			break
+		}
+	}
+
+	return &driver.Message{
+		Data:         entry.Data,
+		EventType:    entry.EventType,
+		Notification: notification,
+		Target:       target,
+		Object:       entry.ObjectData,
+		TemplateData: map[string]interface{}{
+			"eventType": entry.EventType,
+			"object":    entry.ObjectData,
+		},
+	}
+}
@@ -0,0 +1,394 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	//
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/events"
+	"freepik.com/notifik/internal/integrations"
+	"freepik.com/notifik/internal/integrations/driver"
+	"freepik.com/notifik/internal/metrics"
+	integrationsRegistry "freepik.com/notifik/internal/registry/integrations"
+	"freepik.com/notifik/internal/tenancy"
+)
+
+const (
+	//
+	IntegrationNotFoundErrorMessage  = "integration '%s' not found"
+	NotificationNotFoundErrorMessage = "notification '%s/%s' is not registered, message discarded"
+	QueueFullErrorMessage            = "delivery queue of integration '%s' is full, message discarded"
+
+	deliveryAttemptFailedMessage   = "Attempt %d of %d failed for integration '%s', retrying in %s: %s"
+	deliveryDiscardedMessage       = "Message discarded for integration '%s' after %d attempts: %s"
+	deliveryContextFinishedMessage = "Delivery queue of integration '%s' finished by context"
+	deliveryQueueRemovedMessage    = "Delivery queue of integration '%s' removed, pending messages discarded: %d"
+	deliveryRefreshFailedMessage   = "Messages of integration '%s' could not be refreshed, trying again later: %s"
+	deliveryRefreshQueueingError   = "Impossible to queue the refresh of integration '%s': %s"
+	managerContextFinishedMessage  = "DeliveryManager finished by context"
+)
+
+func NewDeliveryManager(dependencies DeliveryManagerDependencies) *DeliveryManager {
+	return &DeliveryManager{
+		queues:       map[string]*queue{},
+		failing:      map[string]bool{},
+		Dependencies: dependencies,
+	}
+}
+
+// Send queues a message to be delivered to the Integration, identified by its key in the IntegrationsRegistry.
+// It does not wait for the delivery, and fails when the Integration does not exist or its queue is full
+func (m *DeliveryManager) Send(integrationName string, msg *driver.Message) (err error) {
+	integration, integrationFound := m.Dependencies.IntegrationsRegistry.GetIntegration(integrationName)
+	if !integrationFound {
+		return fmt.Errorf(IntegrationNotFoundErrorMessage, integrationName)
+	}
+
+	// Sharing of Integrations can change after registering the Notification, so it is checked on each message.
+	// Messages of Notifications not registered anymore are dropped, as their scope is unknown
+	compiled, compiledFound := m.Dependencies.NotificationsRegistry.GetCompiled(msg.Notification)
+	if !compiledFound {
+		return fmt.Errorf(NotificationNotFoundErrorMessage, msg.Notification.Namespace, msg.Notification.Name)
+	}
+	scope := compiled.Scope
+	if !scope.AllowsIntegration(integration) {
+		return fmt.Errorf(tenancy.IntegrationNotSharedError, integrationName, scope.Namespace)
+	}
+
+	return m.enqueue(integrationName, getQueueSize(&integration.Spec.Delivery), &job{
+		jobType: jobTypeSend,
+		message: msg,
+	})
+}
+
+// Resolve queues a message to tell the Integration that its object does not meet the conditions anymore.
+// It does nothing for Integrations whose driver does not resolve messages, nor for the ones not tracking the object,
+// unless a previous message is still waiting in the queue
+func (m *DeliveryManager) Resolve(integrationName string, msg *driver.Message) (err error) {
+	integration, integrationFound := m.Dependencies.IntegrationsRegistry.GetIntegration(integrationName)
+	if !integrationFound {
+		return nil
+	}
+
+	if !integrations.IsResolver(integration) {
+		return nil
+	}
+
+	compiled, compiledFound := m.Dependencies.NotificationsRegistry.GetCompiled(msg.Notification)
+	if !compiledFound || !compiled.Scope.AllowsIntegration(integration) {
+		return nil
+	}
+
+	if !integrations.IsTracking(integration, msg) && m.getPendingJobs(integrationName) == 0 {
+		return nil
+	}
+
+	return m.enqueue(integrationName, getQueueSize(&integration.Spec.Delivery), &job{
+		jobType: jobTypeResolve,
+		message: msg,
+	})
+}
+
+// Forget tells the Integrations keeping state about the messages of the Notification to drop it,
+// except for the ones provided, that are still targeted by the Notification.
+// It must be called when the Notification is removed or stops targeting some Integrations, as nothing else
+// would tell them. Forgetting is queued, so it happens after the messages already waiting in the queue
+func (m *DeliveryManager) Forget(notification *v1alpha1.Notification, keptIntegrations []string) {
+	for _, integration := range m.Dependencies.IntegrationsRegistry.GetIntegrations() {
+		integrationName := integrationsRegistry.GetIntegrationKey(integration)
+		if !integrations.IsForgetter(integration) || slices.Contains(keptIntegrations, integrationName) {
+			continue
+		}
+
+		// Notifications are forgotten right away when the queue is full, so nothing is kept forever
+		err := m.enqueue(integrationName, getQueueSize(&integration.Spec.Delivery), &job{
+			jobType: jobTypeForget,
+			message: &driver.Message{Notification: notification},
+		})
+		if err != nil {
+			integrations.ForgetNotification(integration, notification)
+		}
+	}
+}
+
+// Start asks the Integrations whose backend forgets the messages not sent again to refresh them,
+// every driver.RefreshInterval until the context is done.
+// Refreshes are queued as the rest of jobs, so they are never delivered before a previous resolution
+func (m *DeliveryManager) Start() {
+	logger := log.FromContext(*m.Dependencies.Context)
+
+	ticker := time.NewTicker(driver.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-(*m.Dependencies.Context).Done():
+			logger.Info(managerContextFinishedMessage)
+			return
+		case <-ticker.C:
+			for _, integration := range m.Dependencies.IntegrationsRegistry.GetIntegrations() {
+				if !integrations.IsRefresher(integration) {
+					continue
+				}
+
+				integrationName := integrationsRegistry.GetIntegrationKey(integration)
+				err := m.enqueue(integrationName, getQueueSize(&integration.Spec.Delivery), &job{
+					jobType: jobTypeRefresh,
+				})
+				if err != nil {
+					logger.Info(fmt.Sprintf(deliveryRefreshQueueingError, integrationName, err))
+				}
+			}
+		}
+	}
+}
+
+// getPendingJobs return the number of jobs waiting in the queue of the Integration
+func (m *DeliveryManager) getPendingJobs(integrationName string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	integrationQueue, queueExists := m.queues[integrationName]
+	if !queueExists {
+		return 0
+	}
+	return len(integrationQueue.jobs)
+}
+
+// enqueue puts a job into the queue of the Integration, creating the queue and its worker when needed.
+// The queue is resized when the size configured for the Integration changed, so no restart is needed.
+// The lock is held while queueing, so no job is put into a queue already removed
+func (m *DeliveryManager) enqueue(integrationName string, queueSize int, j *job) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	integrationQueue, queueExists := m.queues[integrationName]
+	if !queueExists {
+		integrationQueue = &queue{
+			jobs: make(chan *job, queueSize),
+			done: make(chan struct{}),
+		}
+		m.queues[integrationName] = integrationQueue
+		go m.processQueue(integrationName, integrationQueue)
+	}
+	resizeQueue(integrationQueue, queueSize)
+
+	select {
+	case integrationQueue.jobs <- j:
+		return nil
+	default:
+		return fmt.Errorf(QueueFullErrorMessage, integrationName)
+	}
+}
+
+// resizeQueue moves the pending jobs of the queue into a new one of the given size, keeping their order.
+// Queues are only shrunk once their pending jobs fit, so no job is discarded by resizing.
+// The old channel is closed, so the worker waiting on it reads the new one. It must be called with the lock held
+func resizeQueue(integrationQueue *queue, queueSize int) {
+	if cap(integrationQueue.jobs) == queueSize || len(integrationQueue.jobs) > queueSize {
+		return
+	}
+
+	jobs := make(chan *job, queueSize)
+	for pending := true; pending; {
+		select {
+		case j := <-integrationQueue.jobs:
+			jobs <- j
+		default:
+			pending = false
+		}
+	}
+
+	close(integrationQueue.jobs)
+	integrationQueue.jobs = jobs
+}
+
+// getJobs return the channel of jobs of the queue, which changes when the queue is resized
+func (m *DeliveryManager) getJobs(integrationQueue *queue) chan *job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return integrationQueue.jobs
+}
+
+// RemoveQueue stops the worker of the Integration and forgets its queue.
+// It must be called when the Integration is removed from the IntegrationsRegistry,
+// so the pending jobs are discarded instead of retried against an Integration that does not exist.
+// The driver forgets what it keeps about the Integration once the worker is done with the job in progress
+func (m *DeliveryManager) RemoveQueue(integration *v1alpha1.Integration) {
+	integrationName := integrationsRegistry.GetIntegrationKey(integration)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.failing, integrationName)
+
+	integrationQueue, queueExists := m.queues[integrationName]
+	if !queueExists {
+		integrations.ForgetIntegration(integration)
+		return
+	}
+	delete(m.queues, integrationName)
+	integrationQueue.removedIntegration = integration
+	close(integrationQueue.done)
+}
+
+// processQueue delivers the jobs of an Integration one by one, until the context is done or the queue is removed
+func (m *DeliveryManager) processQueue(integrationName string, integrationQueue *queue) {
+	logger := log.FromContext(*m.Dependencies.Context)
+
+	for {
+		select {
+		case <-(*m.Dependencies.Context).Done():
+			logger.Info(fmt.Sprintf(deliveryContextFinishedMessage, integrationName))
+			return
+		case <-integrationQueue.done:
+			discarded := m.discardQueue(integrationName, integrationQueue)
+			integrations.ForgetIntegration(integrationQueue.removedIntegration)
+			logger.Info(fmt.Sprintf(deliveryQueueRemovedMessage, integrationName, discarded))
+			return
+		case j, open := <-m.getJobs(integrationQueue):
+			// Channel is closed when the queue is resized, and its jobs are in the new one
+			if !open {
+				continue
+			}
+			if j.jobType == jobTypeRefresh {
+				m.processRefresh(integrationName)
+				continue
+			}
+			if j.jobType == jobTypeForget {
+				m.processForget(integrationName, j)
+				continue
+			}
+			m.processJob(integrationName, integrationQueue, j)
+		}
+	}
+}
+
+// discardQueue drains the jobs left in a removed queue, storing them as dead letters
+func (m *DeliveryManager) discardQueue(integrationName string, integrationQueue *queue) (discarded int) {
+	err := fmt.Errorf(IntegrationNotFoundErrorMessage, integrationName)
+	jobs := m.getJobs(integrationQueue)
+	for {
+		select {
+		case j := <-jobs:
+			if j.jobType == jobTypeRefresh || j.jobType == jobTypeForget {
+				continue
+			}
+			metrics.DeliveryDiscarded.WithLabelValues(integrationName).Inc()
+			m.storeDeadLetter(integrationName, j, 0, err)
+			discarded++
+		default:
+			return discarded
+		}
+	}
+}
+
+// processRefresh sends again the messages the backend of the Integration would forget.
+// It is not retried, as the next refresh will do it
+func (m *DeliveryManager) processRefresh(integrationName string) {
+	ctx := *m.Dependencies.Context
+
+	integration, integrationFound := m.Dependencies.IntegrationsRegistry.GetIntegration(integrationName)
+	if !integrationFound {
+		return
+	}
+
+	startTime := time.Now()
+	err := integrations.RefreshMessages(ctx, integration)
+	metrics.ObserveDeliveryAttempt(integrationName, startTime, err)
+	if err != nil {
+		log.FromContext(ctx).Info(fmt.Sprintf(deliveryRefreshFailedMessage, integrationName, err))
+	}
+}
+
+// processForget tells the driver of the Integration to drop what it keeps about the messages of a Notification
+func (m *DeliveryManager) processForget(integrationName string, j *job) {
+	integration, integrationFound := m.Dependencies.IntegrationsRegistry.GetIntegration(integrationName)
+	if !integrationFound {
+		return
+	}
+
+	integrations.ForgetNotification(integration, j.message.Notification)
+}
+
+// processJob delivers a job retrying it with exponential backoff until it succeeds,
+// it fails permanently or the attempts are exhausted. Retries stop when the queue is removed
+func (m *DeliveryManager) processJob(integrationName string, integrationQueue *queue, j *job) {
+	ctx := *m.Dependencies.Context
+	logger := log.FromContext(ctx).WithValues(
+		"notification", fmt.Sprintf("%s/%s", j.message.Notification.Namespace, j.message.Notification.Name))
+
+	var err error
+	for attempt := 1; ; attempt++ {
+
+		// Integration is read on each attempt, so changes in its configuration are honored while retrying
+		integration, integrationFound := m.Dependencies.IntegrationsRegistry.GetIntegration(integrationName)
+		if !integrationFound {
+			err = fmt.Errorf(IntegrationNotFoundErrorMessage, integrationName)
+			logger.Info(fmt.Sprintf(deliveryDiscardedMessage, integrationName, attempt-1, err))
+			metrics.DeliveryDiscarded.WithLabelValues(integrationName).Inc()
+			m.storeDeadLetter(integrationName, j, attempt-1, err)
+			return
+		}
+
+		startTime := time.Now()
+		switch j.jobType {
+		case jobTypeSend:
+			err = integrations.SendMessage(ctx, integration, j.message)
+		case jobTypeResolve:
+			err = integrations.ResolveMessage(ctx, integration, j.message)
+		}
+		metrics.ObserveDeliveryAttempt(integrationName, startTime, err)
+
+		if err == nil {
+			m.recordResult(integrationName, nil)
+			return
+		}
+
+		permanentError := &driver.PermanentError{}
+		maxAttempts := getMaxAttempts(&integration.Spec.Delivery)
+		if errors.As(err, &permanentError) || attempt >= maxAttempts {
+			logger.Info(fmt.Sprintf(deliveryDiscardedMessage, integrationName, attempt, err))
+			metrics.DeliveryDiscarded.WithLabelValues(integrationName).Inc()
+			m.Dependencies.EventRecorder.Record(j.message.Notification, j.message.Object, events.Warning,
+				events.ReasonDeliveryFailed, events.DeliveryFailedMessage, integrationName, err.Error())
+			m.Dependencies.StatusTracker.RecordFailure(j.message.Notification, err)
+			m.recordResult(integrationName, err)
+			m.storeDeadLetter(integrationName, j, attempt, err)
+			return
+		}
+
+		backoff := getBackoff(&integration.Spec.Delivery, attempt)
+		logger.Info(fmt.Sprintf(deliveryAttemptFailedMessage, attempt, maxAttempts, integrationName, backoff, err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-integrationQueue.done:
+		case <-time.After(backoff):
+		}
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	//
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/integrations/driver"
+	integrationsRegistry "freepik.com/notifik/internal/registry/integrations"
+)
+
+// newWebhookManager return a DeliveryManager with a webhook Integration replying the given status code,
+// and the counter of requests it received
+func newWebhookManager(t *testing.T, statusCode int, maxAttempts int) (*DeliveryManager, string, *atomic.Int32) {
+	requests := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(statusCode)
+	}))
+	t.Cleanup(server.Close)
+
+	integration := &v1alpha1.Integration{}
+	integration.Name = "webhook"
+	integration.Spec.Type = "webhook"
+	integration.Spec.Webhook = v1alpha1.IntegrationWebhook{Url: server.URL, Verb: http.MethodPost}
+	integration.Spec.Delivery = v1alpha1.IntegrationDelivery{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: &metav1.Duration{Duration: time.Millisecond},
+		MaxBackoff:     &metav1.Duration{Duration: time.Millisecond},
+	}
+
+	registry := integrationsRegistry.NewIntegrationsRegistry()
+	registry.AddIntegration(integration)
+
+	ctx := context.Background()
+	manager := NewDeliveryManager(DeliveryManagerDependencies{
+		Context:              &ctx,
+		IntegrationsRegistry: registry,
+	})
+
+	return manager, integrationsRegistry.GetIntegrationKey(integration), requests
+}
+
+func TestProcessJobAttempts(t *testing.T) {
+	tests := []struct {
+		name         string
+		statusCode   int
+		maxAttempts  int
+		wantRequests int32
+		wantFailing  bool
+	}{
+		{
+			name:         "delivered messages are sent once",
+			statusCode:   http.StatusOK,
+			maxAttempts:  3,
+			wantRequests: 1,
+		},
+		{
+			name:         "retryable failures are attempted up to the maximum",
+			statusCode:   http.StatusServiceUnavailable,
+			maxAttempts:  3,
+			wantRequests: 3,
+			wantFailing:  true,
+		},
+		{
+			name:         "rate limited messages are retried",
+			statusCode:   http.StatusTooManyRequests,
+			maxAttempts:  2,
+			wantRequests: 2,
+			wantFailing:  true,
+		},
+		{
+			name:         "permanent failures are not retried",
+			statusCode:   http.StatusBadRequest,
+			maxAttempts:  3,
+			wantRequests: 1,
+			wantFailing:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, integrationName, requests := newWebhookManager(t, tt.statusCode, tt.maxAttempts)
+
+			integrationQueue := &queue{jobs: make(chan *job, 1), done: make(chan struct{})}
+			manager.processJob(integrationName, integrationQueue, &job{
+				jobType: jobTypeSend,
+				message: &driver.Message{Data: "{}", Notification: &v1alpha1.Notification{}},
+			})
+
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("expected %d requests, got %d", tt.wantRequests, got)
+			}
+			if got := manager.failing[integrationName]; got != tt.wantFailing {
+				t.Errorf("expected failing %t, got %t", tt.wantFailing, got)
+			}
+		})
+	}
+}
+
+func TestResizeQueue(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		pending   int
+		newSize   int
+		wantSize  int
+		wantClose bool
+	}{
+		{
+			name:      "queues grow keeping their jobs",
+			size:      2,
+			pending:   2,
+			newSize:   5,
+			wantSize:  5,
+			wantClose: true,
+		},
+		{
+			name:      "queues shrink when their jobs fit",
+			size:      5,
+			pending:   2,
+			newSize:   2,
+			wantSize:  2,
+			wantClose: true,
+		},
+		{
+			name:     "queues do not shrink while their jobs do not fit",
+			size:     5,
+			pending:  3,
+			newSize:  2,
+			wantSize: 5,
+		},
+		{
+			name:     "queues of the same size are kept",
+			size:     5,
+			pending:  1,
+			newSize:  5,
+			wantSize: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldJobs := make(chan *job, tt.size)
+			var pending []*job
+			for i := 0; i < tt.pending; i++ {
+				j := &job{jobType: jobTypeSend}
+				pending = append(pending, j)
+				oldJobs <- j
+			}
+
+			integrationQueue := &queue{jobs: oldJobs}
+			resizeQueue(integrationQueue, tt.newSize)
+
+			if got := cap(integrationQueue.jobs); got != tt.wantSize {
+				t.Fatalf("expected size %d, got %d", tt.wantSize, got)
+			}
+
+			// Jobs are kept in order
+			for i, want := range pending {
+				if got := <-integrationQueue.jobs; got != want {
+					t.Fatalf("job %d is not in order", i)
+				}
+			}
+
+			// Workers waiting on the old channel are woken up to read the new one
+			closed := false
+			select {
+			case _, open := <-oldJobs:
+				closed = !open
+			default:
+			}
+			if closed != tt.wantClose {
+				t.Errorf("expected old channel closed %t, got %t", tt.wantClose, closed)
+			}
+		})
+	}
+}
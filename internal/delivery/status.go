@@ -0,0 +1,104 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delivery
+
+import (
+	"context"
+	"fmt"
+
+	//
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/controller"
+	integrationsRegistry "freepik.com/notifik/internal/registry/integrations"
+)
+
+const (
+	statusUpdateErrorMessage = "Impossible to update the delivery status of integration '%s': %s"
+)
+
+// recordResult updates the Delivering condition of the Integration when the result of its deliveries changes
+func (m *DeliveryManager) recordResult(integrationName string, deliveryErr error) {
+	ctx := *m.Dependencies.Context
+	logger := log.FromContext(ctx)
+
+	deliveryFailing := deliveryErr != nil
+
+	m.mu.Lock()
+	previouslyFailing, resultKnown := m.failing[integrationName]
+	m.failing[integrationName] = deliveryFailing
+	m.mu.Unlock()
+
+	if resultKnown && previouslyFailing == deliveryFailing {
+		return
+	}
+
+	condition := controller.NewCondition(controller.ConditionTypeDelivering, metav1.ConditionTrue,
+		controller.ConditionReasonDeliverySucceeded, controller.ConditionReasonDeliverySucceededMessage)
+
+	if deliveryFailing {
+		condition = controller.NewCondition(controller.ConditionTypeDelivering, metav1.ConditionFalse,
+			controller.ConditionReasonDeliveryFailed,
+			fmt.Sprintf(controller.ConditionReasonDeliveryFailedMessage, deliveryErr.Error()))
+	}
+
+	err := m.updateCondition(ctx, integrationName, condition)
+	if err != nil {
+		logger.Info(fmt.Sprintf(statusUpdateErrorMessage, integrationName, err))
+
+		// Forget the result, so the update is tried again with the next delivery
+		m.mu.Lock()
+		delete(m.failing, integrationName)
+		m.mu.Unlock()
+	}
+}
+
+// updateCondition sets a condition in the status of the Integration, retrying when it was modified meanwhile
+func (m *DeliveryManager) updateCondition(ctx context.Context, integrationName string, condition metav1.Condition) error {
+	if m.Dependencies.Client == nil {
+		return nil
+	}
+
+	// Integrations are registered with the key of their kind, so the right resource is updated
+	kind, name := integrationsRegistry.ParseKey(integrationName)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var integration client.Object
+		var status *v1alpha1.IntegrationStatus
+		if kind == v1alpha1.ClusterIntegrationKind {
+			clusterIntegration := &v1alpha1.ClusterIntegration{}
+			integration, status = clusterIntegration, &clusterIntegration.Status
+		} else {
+			namespacedIntegration := &v1alpha1.Integration{}
+			integration, status = namespacedIntegration, &namespacedIntegration.Status
+		}
+
+		err := m.Dependencies.Client.Get(ctx, types.NamespacedName{Name: name}, integration)
+		if err != nil {
+			return err
+		}
+
+		controller.UpdateCondition(&status.Conditions, condition)
+		return m.Dependencies.Client.Status().Update(ctx, integration)
+	})
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delivery
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	//
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/deadletter"
+	"freepik.com/notifik/internal/events"
+	"freepik.com/notifik/internal/integrations/driver"
+	integrationsRegistry "freepik.com/notifik/internal/registry/integrations"
+	notificationsRegistry "freepik.com/notifik/internal/registry/notifications"
+	"freepik.com/notifik/internal/tracker"
+)
+
+const (
+	// Defaults applied when 'spec.delivery' of the Integration does not set them
+	DefaultMaxAttempts    = 3
+	DefaultInitialBackoff = 1 * time.Second
+	DefaultMaxBackoff     = 1 * time.Minute
+	DefaultQueueSize      = 1000
+)
+
+// jobType represents the action performed with the message of a job
+type jobType string
+
+const (
+	jobTypeSend    jobType = "send"
+	jobTypeResolve jobType = "resolve"
+
+	// jobTypeRefresh sends again the messages the backend would forget. It carries no message
+	jobTypeRefresh jobType = "refresh"
+
+	// jobTypeForget drops what the driver keeps about the messages of a Notification. Its message only carries it
+	jobTypeForget jobType = "forget"
+)
+
+// job represents a message waiting in the queue of an Integration
+type job struct {
+	jobType jobType
+	message *driver.Message
+}
+
+// queue represents the pending jobs of an Integration, processed in order by one worker.
+// Its worker finishes when done is closed, once the Integration is removed
+type queue struct {
+	// jobs is replaced when the queue is resized, so it is only read with the lock of the DeliveryManager held
+	jobs chan *job
+	done chan struct{}
+
+	// removedIntegration is the Integration whose removal closed done
+	removedIntegration *v1alpha1.Integration
+}
+
+type DeliveryManagerDependencies struct {
+	Context *context.Context
+	Client  client.Client
+
+	//
+	IntegrationsRegistry  *integrationsRegistry.IntegrationsRegistry
+	NotificationsRegistry *notificationsRegistry.NotificationsRegistry
+
+	// EventRecorder records the failed deliveries on the Notifications
+	EventRecorder *events.Recorder
+
+	// StatusTracker records the failed deliveries in the status of the Notifications
+	StatusTracker *tracker.StatusTracker
+
+	// DeadLetterStore keeps the messages that could not be delivered. Discarded messages are lost when it is nil
+	DeadLetterStore deadletter.Store
+}
+
+// DeliveryManager delivers messages to Integrations out of the informers' goroutines.
+// Each Integration has its own queue, so slow receivers do not delay the others
+type DeliveryManager struct {
+	mu     sync.Mutex
+	queues map[string]*queue
+
+	// failing stores whether the last delivery failed for each Integration.
+	// Status of Integrations is only updated when it changes
+	failing map[string]bool
+
+	Dependencies DeliveryManagerDependencies
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"fmt"
+
+	//
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+)
+
+const (
+	// Types of the events
+	Normal  = corev1.EventTypeNormal
+	Warning = corev1.EventTypeWarning
+
+	// Reasons of the events recorded for Notifications
+	ReasonTriggered      = "Triggered"
+	ReasonTemplateFailed = "TemplateFailed"
+	ReasonDeliveryFailed = "DeliveryFailed"
+
+	// Messages of the events recorded for Notifications
+	TriggeredMessage               = "Conditions met by %s '%s', message queued for integration '%s'"
+	ConditionTemplateFailedMessage = "Conditions could not be evaluated for %s '%s': %s"
+	MessageTemplateFailedMessage   = "Message could not be rendered for %s '%s': %s"
+	DeliveryFailedMessage          = "Message for %s '%s' could not be delivered to integration '%s': %s"
+)
+
+// Recorder records Kubernetes events about what happens with Notifications.
+// Events are also recorded on the watched objects when requested
+type Recorder struct {
+	EventRecorder record.EventRecorder
+
+	// RecordObjectEvents enables recording the events on the watched objects too
+	RecordObjectEvents bool
+}
+
+func NewRecorder(eventRecorder record.EventRecorder, recordObjectEvents bool) *Recorder {
+	return &Recorder{
+		EventRecorder:      eventRecorder,
+		RecordObjectEvents: recordObjectEvents,
+	}
+}
+
+// Record records an event on the Notification and, when enabled, on the watched object.
+// The message is completed with the kind and the name of the object before the rest of arguments
+func (r *Recorder) Record(notification *v1alpha1.Notification, object map[string]interface{},
+	eventType, reason, messageFmt string, args ...interface{}) {
+
+	if r == nil || r.EventRecorder == nil {
+		return
+	}
+
+	objectData := &unstructured.Unstructured{Object: object}
+	objectName := objectData.GetName()
+	if objectData.GetNamespace() != "" {
+		objectName = objectData.GetNamespace() + "/" + objectName
+	}
+
+	message := fmt.Sprintf(messageFmt, append([]interface{}{objectData.GetKind(), objectName}, args...)...)
+
+	notificationObject, notificationName := getNotificationObject(notification)
+	r.EventRecorder.Event(notificationObject, eventType, reason, message)
+
+	if r.RecordObjectEvents && objectData.GetUID() != "" {
+		r.EventRecorder.Event(objectData, eventType, reason,
+			fmt.Sprintf("%s (notification '%s')", message, notificationName))
+	}
+}
+
+// getNotificationObject return the resource the events of the Notification are recorded on, and its name.
+// ClusterNotifications are registered as Notifications, so their events must point to the right kind
+func getNotificationObject(notification *v1alpha1.Notification) (object runtime.Object, name string) {
+	if notification.Kind == v1alpha1.ClusterNotificationKind {
+		return &v1alpha1.ClusterNotification{ObjectMeta: notification.ObjectMeta}, notification.Name
+	}
+
+	return notification, notification.Namespace + "/" + notification.Name
+}
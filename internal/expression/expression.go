@@ -0,0 +1,127 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expression
+
+import (
+	"fmt"
+	"sync"
+
+	//
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+)
+
+const (
+	compileErrorMessage    = "expression can not be compiled: %s"
+	outputTypeErrorMessage = "expression must return a bool, but returns %s"
+	evaluateErrorMessage   = "expression can not be evaluated: %s"
+	resultTypeErrorMessage = "expression returned %T instead of a bool"
+)
+
+var (
+	environment     *cel.Env
+	environmentErr  error
+	environmentOnce sync.Once
+)
+
+// getEnvironment returns the CEL environment shared by all the expressions.
+// It declares the same variables injected into Go templates
+func getEnvironment() (*cel.Env, error) {
+	environmentOnce.Do(func() {
+		environment, environmentErr = cel.NewEnv(
+			cel.Variable("eventType", cel.StringType),
+			cel.Variable("object", cel.MapType(cel.StringType, cel.DynType)),
+			cel.Variable("previousObject", cel.MapType(cel.StringType, cel.DynType)),
+			cel.Variable("sources", cel.ListType(cel.ListType(cel.MapType(cel.StringType, cel.DynType)))),
+			ext.Strings(),
+			ext.Lists(),
+		)
+	})
+
+	return environment, environmentErr
+}
+
+// Compile parses and type-checks a CEL expression, returning a program ready to be evaluated
+func Compile(expression string) (program cel.Program, err error) {
+	env, err := getEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf(compileErrorMessage, issues.Err())
+	}
+
+	// Results are only known at evaluation time for dynamic expressions
+	if !ast.OutputType().IsExactType(cel.BoolType) && !ast.OutputType().IsExactType(cel.DynType) {
+		return nil, fmt.Errorf(outputTypeErrorMessage, ast.OutputType())
+	}
+
+	return env.Program(ast)
+}
+
+// Evaluate runs a compiled program against the data injected into templates, returning its result
+func Evaluate(program cel.Program, data map[string]interface{}) (result bool, err error) {
+	output, _, err := program.Eval(getActivation(data))
+	if err != nil {
+		return false, fmt.Errorf(evaluateErrorMessage, err)
+	}
+
+	result, ok := output.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf(resultTypeErrorMessage, output.Value())
+	}
+
+	return result, nil
+}
+
+// getActivation converts the data injected into templates into the variables declared for expressions.
+// Missing variables are set empty, so expressions can check them without failing
+func getActivation(data map[string]interface{}) map[string]interface{} {
+	activation := map[string]interface{}{
+		"eventType":      fmt.Sprint(data["eventType"]),
+		"object":         map[string]interface{}{},
+		"previousObject": map[string]interface{}{},
+		"sources":        []interface{}{},
+	}
+
+	if object, ok := data["object"].(map[string]interface{}); ok {
+		activation["object"] = object
+	}
+
+	if previousObject, ok := data["previousObject"].(map[string]interface{}); ok {
+		activation["previousObject"] = previousObject
+	}
+
+	// Sources are stored as pointers, not understood by CEL
+	if sources, ok := data["sources"].([][]*map[string]any); ok {
+		sourceList := make([]interface{}, 0, len(sources))
+		for _, resources := range sources {
+			resourceList := make([]interface{}, 0, len(resources))
+			for _, resource := range resources {
+				if resource != nil {
+					resourceList = append(resourceList, *resource)
+				}
+			}
+			sourceList = append(sourceList, resourceList)
+		}
+		activation["sources"] = sourceList
+	}
+
+	return activation
+}
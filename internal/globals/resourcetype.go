@@ -0,0 +1,222 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package globals
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	//
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+)
+
+const (
+	resourceTypeNameParsingError  = "resource type name '%s' can not be parsed: %s"
+	labelSelectorParsingError     = "label selector '%s' can not be parsed: %s"
+	fieldSelectorParsingError     = "field selector '%s' can not be parsed: %s"
+	namespaceSelectorParsingError = "namespace selector '%s' can not be parsed: %s"
+	namespacesListingError        = "namespaces of resource type '%s' can not be listed: %s"
+)
+
+// ResourceType identifies a group of resources watched by an informer
+type ResourceType struct {
+	Group     string
+	Version   string
+	Resource  string
+	Namespace string
+	Name      string
+
+	LabelSelector string
+	FieldSelector string
+
+	// NamespaceSelector selects the namespaces watched by labels. Resource types using it are expanded
+	// into one resource type per matching namespace, which keeps the selector to know where it comes from
+	NamespaceSelector string
+}
+
+// ResourceTypeFromWatch return the resource type watched by a Notification
+func ResourceTypeFromWatch(watch *v1alpha1.NotificationWatch) ResourceType {
+	return ResourceType{
+		Group:             watch.Group,
+		Version:           watch.Version,
+		Resource:          watch.Resource,
+		Namespace:         watch.Namespace,
+		Name:              watch.Name,
+		LabelSelector:     watch.LabelSelector,
+		FieldSelector:     watch.FieldSelector,
+		NamespaceSelector: watch.NamespaceSelector,
+	}
+}
+
+// ResourceTypeFromExtraResource return the resource type of the extra resources of a Notification
+func ResourceTypeFromExtraResource(extraResource *v1alpha1.NotificationExtraResource) ResourceType {
+	return ResourceType{
+		Group:             extraResource.Group,
+		Version:           extraResource.Version,
+		Resource:          extraResource.Resource,
+		Namespace:         extraResource.Namespace,
+		Name:              extraResource.Name,
+		LabelSelector:     extraResource.LabelSelector,
+		FieldSelector:     extraResource.FieldSelector,
+		NamespaceSelector: extraResource.NamespaceSelector,
+	}
+}
+
+// String return the name used as key for the resource type in the registries:
+// {group}/{version}/{resource}/{namespace}/{name}[/{labelSelector}/{fieldSelector}/{namespaceSelector}]
+// Each part is escaped, as selectors may contain slashes. Selectors are only included when defined
+func (rt ResourceType) String() string {
+	parts := []string{rt.Group, rt.Version, rt.Resource, rt.Namespace, rt.Name}
+	if rt.LabelSelector != "" || rt.FieldSelector != "" || rt.NamespaceSelector != "" {
+		parts = append(parts, rt.LabelSelector, rt.FieldSelector, rt.NamespaceSelector)
+	}
+
+	for partIndex, part := range parts {
+		parts[partIndex] = url.PathEscape(part)
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// GroupVersionResource return the GVR of the resource type
+func (rt ResourceType) GroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    rt.Group,
+		Version:  rt.Version,
+		Resource: rt.Resource,
+	}
+}
+
+// ParseResourceTypeName return the resource type identified by a name built with ResourceType.String
+func ParseResourceTypeName(resourceTypeName string) (rt ResourceType, err error) {
+	parts := strings.Split(resourceTypeName, "/")
+	if len(parts) != 5 && len(parts) != 8 {
+		return rt, fmt.Errorf(resourceTypeNameParsingError, resourceTypeName, "unexpected number of parts")
+	}
+
+	for partIndex, part := range parts {
+		parts[partIndex], err = url.PathUnescape(part)
+		if err != nil {
+			return rt, fmt.Errorf(resourceTypeNameParsingError, resourceTypeName, err)
+		}
+	}
+
+	rt = ResourceType{
+		Group:     parts[0],
+		Version:   parts[1],
+		Resource:  parts[2],
+		Namespace: parts[3],
+		Name:      parts[4],
+	}
+
+	if len(parts) == 8 {
+		rt.LabelSelector = parts[5]
+		rt.FieldSelector = parts[6]
+		rt.NamespaceSelector = parts[7]
+	}
+
+	return rt, nil
+}
+
+// ValidateSelectors checks whether the label, field and namespace selectors of the resource type can be parsed
+func (rt ResourceType) ValidateSelectors() (err error) {
+	_, err = labels.Parse(rt.LabelSelector)
+	if err != nil {
+		return fmt.Errorf(labelSelectorParsingError, rt.LabelSelector, err)
+	}
+
+	_, err = fields.ParseSelector(rt.FieldSelector)
+	if err != nil {
+		return fmt.Errorf(fieldSelectorParsingError, rt.FieldSelector, err)
+	}
+
+	_, err = labels.Parse(rt.NamespaceSelector)
+	if err != nil {
+		return fmt.Errorf(namespaceSelectorParsingError, rt.NamespaceSelector, err)
+	}
+
+	return nil
+}
+
+// Unexpanded return the resource type a namespace of an expanded resource type comes from.
+// It is the one used by Notifications, so it is the same resource type when not using a namespace selector
+func (rt ResourceType) Unexpanded() ResourceType {
+	if rt.NamespaceSelector != "" {
+		rt.Namespace = ""
+	}
+	return rt
+}
+
+// UnexpandedResourceTypeName return the name of the resource type a resource type name comes from.
+// Names that can not be parsed are returned as they are
+func UnexpandedResourceTypeName(resourceTypeName string) string {
+	rt, err := ParseResourceTypeName(resourceTypeName)
+	if err != nil || rt.NamespaceSelector == "" {
+		return resourceTypeName
+	}
+	return rt.Unexpanded().String()
+}
+
+// ExpandResourceTypeNames replaces the resource types using a namespace selector by one resource type
+// per namespace matching it. The rest of them are kept as they are.
+// Resource types that can not be expanded are skipped and returned as failed, so they do not affect the rest.
+// The returned error joins the reasons of all of them
+func ExpandResourceTypeNames(ctx context.Context, c client.Client, resourceTypeNames []string) (expanded []string,
+	failed []string, err error) {
+	expanded = make([]string, 0, len(resourceTypeNames))
+
+	var errs []error
+	for _, resourceTypeName := range resourceTypeNames {
+		rt, parseErr := ParseResourceTypeName(resourceTypeName)
+		if parseErr != nil || rt.NamespaceSelector == "" {
+			expanded = append(expanded, resourceTypeName)
+			continue
+		}
+
+		selector, selectorErr := labels.Parse(rt.NamespaceSelector)
+		if selectorErr != nil {
+			failed = append(failed, resourceTypeName)
+			errs = append(errs, fmt.Errorf(namespaceSelectorParsingError, rt.NamespaceSelector, selectorErr))
+			continue
+		}
+
+		namespaceList := &corev1.NamespaceList{}
+		listErr := c.List(ctx, namespaceList, client.MatchingLabelsSelector{Selector: selector})
+		if listErr != nil {
+			failed = append(failed, resourceTypeName)
+			errs = append(errs, fmt.Errorf(namespacesListingError, resourceTypeName, listErr))
+			continue
+		}
+
+		for _, namespace := range namespaceList.Items {
+			rt.Namespace = namespace.Name
+			expanded = append(expanded, rt.String())
+		}
+	}
+
+	return expanded, failed, errors.Join(errs...)
+}
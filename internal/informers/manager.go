@@ -0,0 +1,264 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informers
+
+import (
+	"fmt"
+	"strings"
+
+	//
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	//
+	"freepik.com/notifik/internal/globals"
+)
+
+const (
+	informerStartedMessage = "Shared informer for '%s' has been started"
+	informerStoppedMessage = "Shared informer for '%s' has been stopped"
+)
+
+// NewSharedInformers return a SharedInformers ready to be subscribed to
+func NewSharedInformers(options SharedInformersOptions, dependencies SharedInformersDependencies) *SharedInformers {
+	return &SharedInformers{
+		informers:    make(map[string]*sharedInformer),
+		Options:      options,
+		Dependencies: dependencies,
+	}
+}
+
+// Subscribe registers the handler for the events of a resource type. Only the events of objects with its name
+// and matching its selectors reach the handler. The informer shared by all the resource types with the same GVR
+// and namespace is started with the first subscription. Objects already known by a running informer are
+// delivered as ADDED events of the initial list
+func (s *SharedInformers) Subscribe(rt globals.ResourceType, handler cache.ResourceEventHandler) (*Subscription, error) {
+	filter, err := newFilteringHandler(rt, handler)
+	if err != nil {
+		return nil, err
+	}
+
+	key := getInformerKey(rt)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shared, exists := s.informers[key]
+	if !exists {
+		shared = s.newSharedInformer(rt)
+		s.informers[key] = shared
+
+		go shared.informer.Run(shared.stopCh)
+		log.FromContext(*s.Dependencies.Context).Info(fmt.Sprintf(informerStartedMessage, key))
+	}
+
+	registration, err := shared.informer.AddEventHandler(filter)
+	if err != nil {
+		s.releaseInformer(key, shared)
+		return nil, err
+	}
+	shared.subscriptions++
+
+	return &Subscription{
+		informers:    s,
+		key:          key,
+		shared:       shared,
+		registration: registration,
+	}, nil
+}
+
+// Unsubscribe removes the handler from the shared informer, and stops the informer when nobody else uses it.
+// Calling it more than once does nothing, so it never releases an informer started later for the same key
+func (sub *Subscription) Unsubscribe() (err error) {
+	sub.once.Do(func() {
+		sub.informers.mu.Lock()
+		defer sub.informers.mu.Unlock()
+
+		err = sub.shared.informer.RemoveEventHandler(sub.registration)
+		sub.shared.subscriptions--
+		sub.informers.releaseInformer(sub.key, sub.shared)
+	})
+
+	return err
+}
+
+// HasSynced returns whether the handler received all the objects of the initial list
+func (sub *Subscription) HasSynced() bool {
+	return sub.registration.HasSynced()
+}
+
+// GetInformerKeys return the keys of the running informers
+func (s *SharedInformers) GetInformerKeys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.informers))
+	for key := range s.informers {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// newSharedInformer creates an informer for the namespace of the resource type, or for all the namespaces
+// when it is not set. Selectors are applied in-process by the subscriptions
+func (s *SharedInformers) newSharedInformer(rt globals.ResourceType) *sharedInformer {
+	namespace := corev1.NamespaceAll
+	if rt.Namespace != "" {
+		namespace = rt.Namespace
+	}
+
+	informer := dynamicinformer.NewFilteredDynamicInformer(globals.Application.KubeRawClient,
+		rt.GroupVersionResource(), namespace, s.Options.InformerDurationToResync, cache.Indexers{},
+		func(options *metav1.ListOptions) {})
+
+	return &sharedInformer{
+		informer: informer.Informer(),
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// releaseInformer stops the informer and forgets it when it has no subscriptions. It must be called with the lock held
+func (s *SharedInformers) releaseInformer(key string, shared *sharedInformer) {
+	if shared.subscriptions > 0 {
+		return
+	}
+
+	close(shared.stopCh)
+	if s.informers[key] == shared {
+		delete(s.informers, key)
+	}
+	log.FromContext(*s.Dependencies.Context).Info(fmt.Sprintf(informerStoppedMessage, key))
+}
+
+// getInformerKey return the key of the informer shared by a resource type. It is built as resource type names,
+// with the GVR and the namespace, as the name and the selectors are filtered in-process
+func getInformerKey(rt globals.ResourceType) string {
+	return globals.ResourceType{
+		Group:     rt.Group,
+		Version:   rt.Version,
+		Resource:  rt.Resource,
+		Namespace: rt.Namespace,
+	}.String()
+}
+
+// filteringHandler delivers to the handler only the events of objects with the namespace and name, when defined,
+// and matching the label and field selectors. Objects starting or stopping to match the selectors are delivered
+// as ADDED or DELETED events, as an informer using them server-side does.
+// Deletions whose final state is unknown are unwrapped, so handlers always receive unstructured objects
+type filteringHandler struct {
+	namespace     string
+	name          string
+	labelSelector labels.Selector
+	fieldSelector fields.Selector
+	handler       cache.ResourceEventHandler
+}
+
+// newFilteringHandler return a filteringHandler delivering to the handler the events of the resource type
+func newFilteringHandler(rt globals.ResourceType, handler cache.ResourceEventHandler) (*filteringHandler, error) {
+	err := rt.ValidateSelectors()
+	if err != nil {
+		return nil, err
+	}
+
+	// Selectors were just validated
+	labelSelector, _ := labels.Parse(rt.LabelSelector)
+	fieldSelector, _ := fields.ParseSelector(rt.FieldSelector)
+
+	return &filteringHandler{
+		namespace:     rt.Namespace,
+		name:          rt.Name,
+		labelSelector: labelSelector,
+		fieldSelector: fieldSelector,
+		handler:       handler,
+	}, nil
+}
+
+func (f *filteringHandler) OnAdd(obj interface{}, isInInitialList bool) {
+	if f.matches(obj) {
+		f.handler.OnAdd(obj, isInInitialList)
+	}
+}
+
+func (f *filteringHandler) OnUpdate(oldObj, newObj interface{}) {
+	oldMatches := f.matches(oldObj)
+	newMatches := f.matches(newObj)
+
+	switch {
+	case oldMatches && newMatches:
+		f.handler.OnUpdate(oldObj, newObj)
+	case newMatches:
+		f.handler.OnAdd(newObj, false)
+	case oldMatches:
+		f.handler.OnDelete(newObj)
+	}
+}
+
+func (f *filteringHandler) OnDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	if f.matches(obj) {
+		f.handler.OnDelete(obj)
+	}
+}
+
+// matches checks whether the object is unstructured, has the namespace and name, when defined,
+// and matches the selectors
+func (f *filteringHandler) matches(obj interface{}) bool {
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return false
+	}
+
+	if f.namespace != "" && object.GetNamespace() != f.namespace {
+		return false
+	}
+
+	if f.name != "" && object.GetName() != f.name {
+		return false
+	}
+
+	if !f.labelSelector.Matches(labels.Set(object.GetLabels())) {
+		return false
+	}
+
+	return f.fieldSelector.Matches(getFieldSet(object, f.fieldSelector))
+}
+
+// getFieldSet return the values of the object for the fields used by the selector, like 'status.phase'.
+// Fields not found in the object are empty
+func getFieldSet(object *unstructured.Unstructured, selector fields.Selector) fields.Set {
+	fieldSet := fields.Set{}
+	for _, requirement := range selector.Requirements() {
+		value, found, err := unstructured.NestedFieldNoCopy(object.Object, strings.Split(requirement.Field, ".")...)
+		if !found || err != nil || value == nil {
+			fieldSet[requirement.Field] = ""
+			continue
+		}
+		fieldSet[requirement.Field] = fmt.Sprint(value)
+	}
+
+	return fieldSet
+}
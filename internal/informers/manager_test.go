@@ -0,0 +1,176 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informers
+
+import (
+	"context"
+	"testing"
+
+	//
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
+	//
+	"freepik.com/notifik/internal/globals"
+)
+
+// newPod return a pod with the provided namespace, name, labels and phase
+func newPod(namespace, name string, labels map[string]interface{}, phase string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"namespace": namespace,
+			"name":      name,
+			"labels":    labels,
+		},
+		"status": map[string]interface{}{"phase": phase},
+	}}
+}
+
+func TestFilteringHandler(t *testing.T) {
+	resourceType := globals.ResourceType{
+		Version:       "v1",
+		Resource:      "pods",
+		Namespace:     "default",
+		LabelSelector: "app=api",
+		FieldSelector: "status.phase!=Running",
+	}
+
+	matching := newPod("default", "api", map[string]interface{}{"app": "api"}, "Failed")
+	otherLabels := newPod("default", "api", map[string]interface{}{"app": "web"}, "Failed")
+	running := newPod("default", "api", map[string]interface{}{"app": "api"}, "Running")
+	otherNamespace := newPod("kube-system", "api", map[string]interface{}{"app": "api"}, "Failed")
+
+	tests := []struct {
+		name       string
+		deliver    func(handler cache.ResourceEventHandler)
+		wantEvents []string
+	}{
+		{
+			name:       "matching objects are added",
+			deliver:    func(handler cache.ResourceEventHandler) { handler.OnAdd(matching, false) },
+			wantEvents: []string{"ADDED"},
+		},
+		{
+			name:    "objects not matching the label selector are filtered",
+			deliver: func(handler cache.ResourceEventHandler) { handler.OnAdd(otherLabels, false) },
+		},
+		{
+			name:    "objects not matching the field selector are filtered",
+			deliver: func(handler cache.ResourceEventHandler) { handler.OnAdd(running, false) },
+		},
+		{
+			name:    "objects of other namespaces are filtered",
+			deliver: func(handler cache.ResourceEventHandler) { handler.OnAdd(otherNamespace, false) },
+		},
+		{
+			name:       "objects matching before and after are modified",
+			deliver:    func(handler cache.ResourceEventHandler) { handler.OnUpdate(matching, matching) },
+			wantEvents: []string{"MODIFIED"},
+		},
+		{
+			name:       "objects starting to match are added",
+			deliver:    func(handler cache.ResourceEventHandler) { handler.OnUpdate(running, matching) },
+			wantEvents: []string{"ADDED"},
+		},
+		{
+			name:       "objects stopping to match are deleted",
+			deliver:    func(handler cache.ResourceEventHandler) { handler.OnUpdate(matching, otherLabels) },
+			wantEvents: []string{"DELETED"},
+		},
+		{
+			name:    "objects matching neither before nor after are filtered",
+			deliver: func(handler cache.ResourceEventHandler) { handler.OnUpdate(running, otherLabels) },
+		},
+		{
+			name: "deletions with unknown final state are unwrapped",
+			deliver: func(handler cache.ResourceEventHandler) {
+				handler.OnDelete(cache.DeletedFinalStateUnknown{Key: "default/api", Obj: matching})
+			},
+			wantEvents: []string{"DELETED"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			events := []string{}
+			handler, err := newFilteringHandler(resourceType, cache.ResourceEventHandlerFuncs{
+				AddFunc:    func(obj interface{}) { events = append(events, "ADDED") },
+				UpdateFunc: func(oldObj, newObj interface{}) { events = append(events, "MODIFIED") },
+				DeleteFunc: func(obj interface{}) {
+					if _, ok := obj.(*unstructured.Unstructured); ok {
+						events = append(events, "DELETED")
+					}
+				},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error creating the handler: %v", err)
+			}
+
+			test.deliver(handler)
+
+			if len(events) != len(test.wantEvents) {
+				t.Fatalf("expected events %v, got %v", test.wantEvents, events)
+			}
+			for index := range events {
+				if events[index] != test.wantEvents[index] {
+					t.Errorf("expected events %v, got %v", test.wantEvents, events)
+				}
+			}
+		})
+	}
+}
+
+func TestNewFilteringHandlerRejectsBrokenSelectors(t *testing.T) {
+	_, err := newFilteringHandler(globals.ResourceType{
+		Version:       "v1",
+		Resource:      "pods",
+		LabelSelector: "app in (api",
+	}, cache.ResourceEventHandlerFuncs{})
+	if err == nil {
+		t.Fatalf("expected an error for a broken label selector")
+	}
+}
+
+func TestSubscriptionUnsubscribeIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	s := NewSharedInformers(SharedInformersOptions{}, SharedInformersDependencies{Context: &ctx})
+
+	// Informers are not started here, so only the bookkeeping of subscriptions is checked
+	first := &sharedInformer{informer: cache.NewSharedIndexInformer(nil, &unstructured.Unstructured{}, 0, cache.Indexers{}),
+		stopCh: make(chan struct{}), subscriptions: 1}
+	s.informers["v1/pods"] = first
+
+	registration, _ := first.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{})
+	subscription := &Subscription{informers: s, key: "v1/pods", shared: first, registration: registration}
+
+	_ = subscription.Unsubscribe()
+	if _, exists := s.informers["v1/pods"]; exists {
+		t.Fatalf("expected the informer to be released")
+	}
+
+	// A newer informer under the same key is not released by unsubscribing again
+	second := &sharedInformer{informer: cache.NewSharedIndexInformer(nil, &unstructured.Unstructured{}, 0, cache.Indexers{}),
+		stopCh: make(chan struct{}), subscriptions: 1}
+	s.informers["v1/pods"] = second
+
+	_ = subscription.Unsubscribe()
+	if s.informers["v1/pods"] != second || second.subscriptions != 1 {
+		t.Fatalf("expected the newer informer to be kept with its subscription")
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	//
+	"k8s.io/client-go/tools/cache"
+)
+
+// SharedInformersOptions represents available options that can be passed to SharedInformers on start
+type SharedInformersOptions struct {
+	// Duration to wait until resync all the objects
+	InformerDurationToResync time.Duration
+}
+
+type SharedInformersDependencies struct {
+	Context *context.Context
+}
+
+// SharedInformers shares one informer per GVR and namespace among watchers and sources.
+// Subscribers are filtered by name, label and field selectors in-process, so watching the same resources with
+// different filters does not list and cache them several times. Informers cache every object of their GVR
+// and namespace, even those that no selector matches.
+// Informers are not shared by GVR only on purpose: resource types restricted to a namespace get their own informer,
+// so the controller only needs permissions on that namespace and only caches its objects. The trade-off is that
+// objects watched both in all the namespaces and in some namespace are listed and cached twice
+type SharedInformers struct {
+	mu        sync.Mutex
+	informers map[string]*sharedInformer
+
+	Options      SharedInformersOptions
+	Dependencies SharedInformersDependencies
+}
+
+// sharedInformer wraps an informer and the number of subscriptions using it
+type sharedInformer struct {
+	informer      cache.SharedIndexInformer
+	stopCh        chan struct{}
+	subscriptions int
+}
+
+// Subscription represents a handler registered into a shared informer
+type Subscription struct {
+	informers    *SharedInformers
+	key          string
+	shared       *sharedInformer
+	registration cache.ResourceEventHandlerRegistration
+
+	// once makes unsubscribing idempotent
+	once sync.Once
+}
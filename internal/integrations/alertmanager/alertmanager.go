@@ -0,0 +1,347 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	//
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/integrations/driver"
+	"freepik.com/notifik/internal/integrations/webhook"
+)
+
+const (
+	// alertsEndpoint is the path of Alertmanager API where alerts are posted
+	alertsEndpoint = "/api/v2/alerts"
+
+	// healthEndpoint is the path of Alertmanager that reports whether it is healthy
+	healthEndpoint = "/-/healthy"
+
+	//
+	ConfigurationMissingErrorMessage = "alertmanager configuration missing for integration '%s'"
+	TemplateErrorMessage             = "error rendering %s '%s' of the alert: %s"
+	HttpRequestCreationErrorMessage  = "error creating http request: %s"
+	HttpRequestSendingErrorMessage   = "error sending http request: %s"
+	HttpResponseErrorMessage         = "alertmanager replied with status code %d: %s"
+
+	// alertLifetime is how long a firing alert lasts in Alertmanager when it is not sent again.
+	// Alerts are sent again every driver.RefreshInterval, so it covers some failed refreshes, like Prometheus does
+	alertLifetime = 4 * driver.RefreshInterval
+)
+
+// Driver implements the delivery of alerts to Alertmanager.
+// It remembers the alerts that are firing, so they are resolved when their objects
+// stop meeting the conditions or are deleted, and sent again before Alertmanager considers them resolved.
+// Firing alerts are kept in memory, so they are resolved by Alertmanager when they are not sent again after restarts
+type Driver struct {
+	mu sync.Mutex
+
+	// firing stores the alerts that are currently firing, indexed by
+	// integration, Notification and UID of the object that triggered them
+	firing map[string]*webhook.AlertmanagerAlert
+}
+
+// NewDriver return an Alertmanager driver ready to be used
+func NewDriver() *Driver {
+	return &Driver{
+		firing: make(map[string]*webhook.AlertmanagerAlert),
+	}
+}
+
+// Validate checks whether the Integration carries a usable Alertmanager configuration
+func (d *Driver) Validate(integration *v1alpha1.Integration) (err error) {
+	if reflect.ValueOf(integration.Spec.Alertmanager).IsZero() {
+		return fmt.Errorf(ConfigurationMissingErrorMessage, integration.Name)
+	}
+
+	_, err = webhook.ParseUrl(integration.Spec.Alertmanager.Url)
+	return err
+}
+
+// SendMessage builds an alert from the Notification and posts it to Alertmanager.
+// Alerts for deleted objects are posted already resolved
+func (d *Driver) SendMessage(ctx context.Context, integration *v1alpha1.Integration, msg *driver.Message) (err error) {
+
+	// Templates will not render differently by retrying
+	alert, err := buildAlert(msg)
+	if err != nil {
+		return driver.NewPermanentError(err)
+	}
+
+	alertKey := getAlertKey(integration, msg)
+
+	// Keep the original starting time while the alert is firing
+	d.mu.Lock()
+	firingAlert, alertFiring := d.firing[alertKey]
+	d.mu.Unlock()
+
+	alert.StartsAt = time.Now().UTC().Format(time.RFC3339)
+	if alertFiring {
+		alert.StartsAt = firingAlert.StartsAt
+	}
+
+	alert.EndsAt = time.Now().Add(alertLifetime).UTC().Format(time.RFC3339)
+	if msg.EventType == watch.Deleted {
+		alert.EndsAt = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	err = postAlerts(ctx, &integration.Spec.Alertmanager, webhook.AlertmanagerAlertList{*alert})
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if msg.EventType == watch.Deleted {
+		delete(d.firing, alertKey)
+		return nil
+	}
+	d.firing[alertKey] = alert
+
+	return nil
+}
+
+// Tracking reports whether the alert of the object is firing
+func (d *Driver) Tracking(integration *v1alpha1.Integration, msg *driver.Message) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, alertFiring := d.firing[getAlertKey(integration, msg)]
+	return alertFiring
+}
+
+// ResolveMessage posts the alert of the object as resolved when it is firing
+func (d *Driver) ResolveMessage(ctx context.Context, integration *v1alpha1.Integration, msg *driver.Message) (err error) {
+
+	alertKey := getAlertKey(integration, msg)
+
+	d.mu.Lock()
+	firingAlert, alertFiring := d.firing[alertKey]
+	d.mu.Unlock()
+
+	if !alertFiring {
+		return nil
+	}
+
+	resolvedAlert := *firingAlert
+	resolvedAlert.EndsAt = time.Now().UTC().Format(time.RFC3339)
+
+	err = postAlerts(ctx, &integration.Spec.Alertmanager, webhook.AlertmanagerAlertList{resolvedAlert})
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	delete(d.firing, alertKey)
+	d.mu.Unlock()
+
+	return nil
+}
+
+// RefreshMessages posts again the alerts of the Integration that are firing, extending their ending time.
+// Alertmanager resolves the alerts that are not sent again before they end
+func (d *Driver) RefreshMessages(ctx context.Context, integration *v1alpha1.Integration) (err error) {
+	alertKeyPrefix := getIntegrationKey(integration) + "/"
+	endsAt := time.Now().Add(alertLifetime).UTC().Format(time.RFC3339)
+
+	d.mu.Lock()
+	alerts := webhook.AlertmanagerAlertList{}
+	for alertKey, firingAlert := range d.firing {
+		if !strings.HasPrefix(alertKey, alertKeyPrefix) {
+			continue
+		}
+		refreshedAlert := *firingAlert
+		refreshedAlert.EndsAt = endsAt
+		alerts = append(alerts, refreshedAlert)
+	}
+	d.mu.Unlock()
+
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	return postAlerts(ctx, &integration.Spec.Alertmanager, alerts)
+}
+
+// ForgetNotification stops refreshing the alerts the Notification fired through the Integration.
+// They are not resolved, so Alertmanager resolves them once they end
+func (d *Driver) ForgetNotification(integration *v1alpha1.Integration, notification *v1alpha1.Notification) {
+	alertKeyPrefix := strings.Join([]string{
+		getIntegrationKey(integration), notification.Namespace, notification.Name, "",
+	}, "/")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for alertKey := range d.firing {
+		if strings.HasPrefix(alertKey, alertKeyPrefix) {
+			delete(d.firing, alertKey)
+		}
+	}
+}
+
+// ForgetIntegration stops refreshing all the alerts fired through the Integration
+func (d *Driver) ForgetIntegration(integration *v1alpha1.Integration) {
+	alertKeyPrefix := getIntegrationKey(integration) + "/"
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for alertKey := range d.firing {
+		if strings.HasPrefix(alertKey, alertKeyPrefix) {
+			delete(d.firing, alertKey)
+		}
+	}
+}
+
+// Health checks whether Alertmanager reports itself as healthy
+func (d *Driver) Health(ctx context.Context, integration *v1alpha1.Integration) (err error) {
+	params := &integration.Spec.Alertmanager
+
+	responseBody, statusCode, err := doRequest(ctx, params, http.MethodGet, healthEndpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	if statusCode != http.StatusOK {
+		return driver.NewHttpResponseError(HttpResponseErrorMessage, statusCode, responseBody)
+	}
+
+	return nil
+}
+
+// getAlertKey return the key used to index the alert of an object in the firing alerts
+func getAlertKey(integration *v1alpha1.Integration, msg *driver.Message) string {
+	objectUid := string((&unstructured.Unstructured{Object: msg.Object}).GetUID())
+
+	return strings.Join([]string{
+		getIntegrationKey(integration), msg.Notification.Namespace, msg.Notification.Name, objectUid,
+	}, "/")
+}
+
+// getIntegrationKey return the part of the alert keys identifying the Integration.
+// ClusterIntegrations are prefixed with their kind, as they can have the same name as Integrations
+func getIntegrationKey(integration *v1alpha1.Integration) string {
+	if integration.Kind == v1alpha1.ClusterIntegrationKind {
+		return integration.Kind + "/" + integration.Name
+	}
+	return integration.Name
+}
+
+// buildAlert renders the labels and annotations defined in the Notification to compose an alert
+func buildAlert(msg *driver.Message) (alert *webhook.AlertmanagerAlert, err error) {
+	params := &msg.Target.Alertmanager
+
+	alert = &webhook.AlertmanagerAlert{
+		Labels:      map[string]string{},
+		Annotations: map[string]string{},
+	}
+
+	for labelKey, labelTemplate := range params.Labels {
+		alert.Labels[labelKey], err = msg.Templates.Evaluate(labelTemplate, msg.TemplateData)
+		if err != nil {
+			return alert, fmt.Errorf(TemplateErrorMessage, "label", labelKey, err)
+		}
+	}
+
+	if _, alertNameFound := alert.Labels["alertname"]; !alertNameFound {
+		alert.Labels["alertname"] = msg.Notification.Name
+	}
+
+	for annotationKey, annotationTemplate := range params.Annotations {
+		alert.Annotations[annotationKey], err = msg.Templates.Evaluate(annotationTemplate, msg.TemplateData)
+		if err != nil {
+			return alert, fmt.Errorf(TemplateErrorMessage, "annotation", annotationKey, err)
+		}
+	}
+
+	if _, descriptionFound := alert.Annotations["description"]; !descriptionFound && msg.Data != "" {
+		alert.Annotations["description"] = msg.Data
+	}
+
+	if params.GeneratorUrl != "" {
+		alert.GeneratorUrl, err = msg.Templates.Evaluate(params.GeneratorUrl, msg.TemplateData)
+		if err != nil {
+			return alert, fmt.Errorf(TemplateErrorMessage, "field", "generatorUrl", err)
+		}
+	}
+
+	return alert, nil
+}
+
+// postAlerts sends a list of alerts to Alertmanager
+func postAlerts(ctx context.Context, params *v1alpha1.IntegrationAlertmanager, alerts webhook.AlertmanagerAlertList) (err error) {
+	payload, err := json.Marshal(alerts)
+	if err != nil {
+		return fmt.Errorf(HttpRequestCreationErrorMessage, err)
+	}
+
+	responseBody, statusCode, err := doRequest(ctx, params, http.MethodPost, alertsEndpoint, payload)
+	if err != nil {
+		return err
+	}
+
+	if statusCode != http.StatusOK {
+		return driver.NewHttpResponseError(HttpResponseErrorMessage, statusCode, responseBody)
+	}
+
+	return nil
+}
+
+// doRequest performs a request against an endpoint of Alertmanager, and returns the body of the response
+func doRequest(ctx context.Context, params *v1alpha1.IntegrationAlertmanager, method, endpoint string, payload []byte) (responseBody []byte, statusCode int, err error) {
+	requestUrl := strings.TrimSuffix(params.Url, "/") + endpoint
+
+	httpRequest, err := http.NewRequestWithContext(ctx, method, requestUrl, bytes.NewBuffer(payload))
+	if err != nil {
+		return responseBody, statusCode, fmt.Errorf(HttpRequestCreationErrorMessage, err)
+	}
+
+	for headerKey, headerValue := range params.Headers {
+		httpRequest.Header.Set(headerKey, headerValue)
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	httpClient := driver.NewHttpClient(params.Timeout)
+	httpResponse, err := httpClient.Do(httpRequest)
+	if err != nil {
+		return responseBody, statusCode, fmt.Errorf(HttpRequestSendingErrorMessage, err)
+	}
+	defer httpResponse.Body.Close()
+
+	responseBody, err = io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return responseBody, httpResponse.StatusCode, fmt.Errorf(HttpRequestSendingErrorMessage, err)
+	}
+
+	return responseBody, httpResponse.StatusCode, nil
+}
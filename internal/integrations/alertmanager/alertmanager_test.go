@@ -0,0 +1,138 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	//
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/integrations/driver"
+	"freepik.com/notifik/internal/integrations/webhook"
+)
+
+// alertmanagerServer records the alerts posted to a fake Alertmanager
+type alertmanagerServer struct {
+	mu     sync.Mutex
+	alerts webhook.AlertmanagerAlertList
+}
+
+func (s *alertmanagerServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	alerts := webhook.AlertmanagerAlertList{}
+	_ = json.NewDecoder(r.Body).Decode(&alerts)
+
+	s.mu.Lock()
+	s.alerts = append(s.alerts, alerts...)
+	s.mu.Unlock()
+}
+
+// popAlerts return the alerts posted since the last call
+func (s *alertmanagerServer) popAlerts() webhook.AlertmanagerAlertList {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	alerts := s.alerts
+	s.alerts = nil
+	return alerts
+}
+
+// newMessage return a message of the Notification for the object with the provided UID
+func newMessage(notification *v1alpha1.Notification, objectUid string) *driver.Message {
+	return &driver.Message{
+		EventType:    watch.Added,
+		Notification: notification,
+		Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"uid": objectUid},
+		},
+	}
+}
+
+func TestDriverForgetNotification(t *testing.T) {
+	server := &alertmanagerServer{}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	integration := &v1alpha1.Integration{
+		ObjectMeta: metav1.ObjectMeta{Name: "alertmanager"},
+		Spec: v1alpha1.IntegrationSpec{
+			Type:         "alertmanager",
+			Alertmanager: v1alpha1.IntegrationAlertmanager{Url: httpServer.URL},
+		},
+	}
+	removedNotification := &v1alpha1.Notification{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "failed-pods"},
+	}
+	keptNotification := &v1alpha1.Notification{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "failed-pods-2"},
+	}
+
+	ctx := context.Background()
+	d := NewDriver()
+
+	for _, msg := range []*driver.Message{
+		newMessage(removedNotification, "1234"),
+		newMessage(removedNotification, "5678"),
+		newMessage(keptNotification, "1234"),
+	} {
+		err := d.SendMessage(ctx, integration, msg)
+		if err != nil {
+			t.Fatalf("unexpected error sending the message: %v", err)
+		}
+	}
+	server.popAlerts()
+
+	// Alerts of other Integrations are not affected
+	otherIntegration := integration.DeepCopy()
+	otherIntegration.Kind = v1alpha1.ClusterIntegrationKind
+	d.ForgetNotification(otherIntegration, removedNotification)
+
+	err := d.RefreshMessages(ctx, integration)
+	if err != nil {
+		t.Fatalf("unexpected error refreshing the alerts: %v", err)
+	}
+	if alerts := server.popAlerts(); len(alerts) != 3 {
+		t.Fatalf("expected 3 alerts to be refreshed, got %d", len(alerts))
+	}
+
+	// Alerts of the removed Notification stop being refreshed, and are not tracked anymore
+	d.ForgetNotification(integration, removedNotification)
+
+	err = d.RefreshMessages(ctx, integration)
+	if err != nil {
+		t.Fatalf("unexpected error refreshing the alerts: %v", err)
+	}
+	alerts := server.popAlerts()
+	if len(alerts) != 1 || alerts[0].Labels["alertname"] != keptNotification.Name {
+		t.Fatalf("expected only the alert of the kept notification to be refreshed, got %v", alerts)
+	}
+
+	if d.Tracking(integration, newMessage(removedNotification, "1234")) {
+		t.Fatalf("expected the alerts of the removed notification not to be tracked")
+	}
+	if !d.Tracking(integration, newMessage(keptNotification, "1234")) {
+		t.Fatalf("expected the alerts of the kept notification to be tracked")
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	//
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/template"
+)
+
+const (
+	// DefaultHttpTimeout is the maximum time to wait for a backend to reply when it is not configured
+	DefaultHttpTimeout = 10 * time.Second
+
+	// MaxResponseBodyLength is the maximum number of bytes of a response body included in errors
+	MaxResponseBodyLength = 512
+
+	// RefreshInterval is the time between refreshes of the messages kept by drivers implementing Refresher
+	RefreshInterval = 1 * time.Minute
+)
+
+// Message represents a rendered notification ready to be delivered by a Driver
+type Message struct {
+	// Data is the result of rendering 'message.data' from the Notification
+	Data string
+
+	// EventType is the type of the event that triggered the message
+	EventType watch.EventType
+
+	// Notification is the resource whose conditions were met
+	Notification *v1alpha1.Notification
+
+	// Target is the message of the Notification being delivered, with the options for the Integration
+	Target v1alpha1.NotificationMessage
+
+	// Object is the watched object that met the conditions
+	Object map[string]interface{}
+
+	// TemplateData is the data injected when rendering the templates of the Notification.
+	// It is available for drivers that render their own templates
+	TemplateData map[string]interface{}
+
+	// Templates are the parsed templates of the Notification. Those not found are parsed on demand
+	Templates template.Templates
+}
+
+// Driver represents a backend able to deliver messages for a type of Integration.
+// Each driver is registered under the value expected in 'spec.type' of Integration resources
+type Driver interface {
+	// Validate checks whether the Integration carries all the configuration needed by the driver.
+	// It is executed when the Integration is reconciled, so wrong resources are detected before sending anything
+	Validate(integration *v1alpha1.Integration) error
+
+	// SendMessage delivers an already rendered message using the configuration of the Integration
+	SendMessage(ctx context.Context, integration *v1alpha1.Integration, msg *Message) error
+
+	// Health reports whether the backend configured in the Integration is reachable
+	Health(ctx context.Context, integration *v1alpha1.Integration) error
+}
+
+// Resolver is implemented by drivers that keep track of the objects that triggered messages.
+// They are told when those objects stop meeting the conditions of the Notification
+type Resolver interface {
+	// Tracking reports whether the object of the message is being tracked, so it needs to be resolved.
+	// It is called for every message that does not meet the conditions, so it must not block
+	Tracking(integration *v1alpha1.Integration, msg *Message) bool
+
+	// ResolveMessage informs the backend that the object of the message does not meet the conditions anymore
+	ResolveMessage(ctx context.Context, integration *v1alpha1.Integration, msg *Message) error
+}
+
+// Refresher is implemented by drivers whose backend forgets the messages that are not sent again.
+// They are asked to send them again every RefreshInterval
+type Refresher interface {
+	// RefreshMessages sends again the messages of the Integration that are still valid
+	RefreshMessages(ctx context.Context, integration *v1alpha1.Integration) error
+}
+
+// Forgetter is implemented by drivers that keep state about the messages delivered for each Notification.
+// They are told when a Notification stops delivering to the Integration, or the Integration is removed,
+// so that state is not kept forever
+type Forgetter interface {
+	// ForgetNotification drops what is kept about the messages the Notification delivered through the Integration
+	ForgetNotification(integration *v1alpha1.Integration, notification *v1alpha1.Notification)
+
+	// ForgetIntegration drops what is kept about all the messages delivered through the Integration
+	ForgetIntegration(integration *v1alpha1.Integration)
+}
+
+// PermanentError wraps the errors that will not be solved by retrying the delivery of a message
+type PermanentError struct {
+	Err error
+}
+
+// NewPermanentError marks an error as not solvable by retrying the delivery
+func NewPermanentError(err error) error {
+	return &PermanentError{Err: err}
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// NewHttpClient return an HTTP client that waits for the provided timeout, or the default one when it is not set
+func NewHttpClient(timeout *metav1.Duration) *http.Client {
+	clientTimeout := DefaultHttpTimeout
+	if timeout != nil && timeout.Duration > 0 {
+		clientTimeout = timeout.Duration
+	}
+
+	return &http.Client{Timeout: clientTimeout}
+}
+
+// TruncateResponseBody return the body of a response shortened to be included in errors and logs
+func TruncateResponseBody(body []byte) string {
+	if len(body) <= MaxResponseBodyLength {
+		return string(body)
+	}
+	return string(body[:MaxResponseBodyLength]) + "..."
+}
+
+// NewHttpResponseError return the error for a response that was not successful.
+// Client errors are permanent, except those asking to wait or to try again later
+func NewHttpResponseError(errorMessage string, statusCode int, body []byte) error {
+	err := fmt.Errorf(errorMessage, statusCode, TruncateResponseBody(body))
+
+	if statusCode >= 400 && statusCode < 500 &&
+		statusCode != http.StatusRequestTimeout && statusCode != http.StatusTooManyRequests {
+		return NewPermanentError(err)
+	}
+
+	return err
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewHttpResponseError(t *testing.T) {
+	tests := []struct {
+		statusCode    int
+		wantPermanent bool
+	}{
+		{statusCode: http.StatusBadRequest, wantPermanent: true},
+		{statusCode: http.StatusUnauthorized, wantPermanent: true},
+		{statusCode: http.StatusForbidden, wantPermanent: true},
+		{statusCode: http.StatusNotFound, wantPermanent: true},
+		{statusCode: http.StatusUnprocessableEntity, wantPermanent: true},
+		{statusCode: http.StatusRequestTimeout, wantPermanent: false},
+		{statusCode: http.StatusTooManyRequests, wantPermanent: false},
+		{statusCode: http.StatusInternalServerError, wantPermanent: false},
+		{statusCode: http.StatusBadGateway, wantPermanent: false},
+		{statusCode: http.StatusServiceUnavailable, wantPermanent: false},
+		{statusCode: http.StatusGatewayTimeout, wantPermanent: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			err := NewHttpResponseError("response with status %d: %s", tt.statusCode, []byte("invalid_payload"))
+
+			permanentError := &PermanentError{}
+			if errors.As(err, &permanentError) != tt.wantPermanent {
+				t.Errorf("expected permanent %v, got error: %v", tt.wantPermanent, err)
+			}
+			if !strings.Contains(err.Error(), "invalid_payload") {
+				t.Errorf("expected the body in the error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewHttpResponseErrorTruncatesBody(t *testing.T) {
+	body := []byte(strings.Repeat("a", MaxResponseBodyLength) + "tail")
+
+	err := NewHttpResponseError("response with status %d: %s", http.StatusInternalServerError, body)
+
+	if strings.Contains(err.Error(), "tail") {
+		t.Errorf("expected the body truncated to %d bytes, got: %v", MaxResponseBodyLength, err)
+	}
+	if !strings.HasSuffix(err.Error(), "...") {
+		t.Errorf("expected truncated bodies to be marked, got: %v", err)
+	}
+}
+
+func TestPermanentErrorUnwraps(t *testing.T) {
+	cause := errors.New("cause")
+
+	err := NewPermanentError(cause)
+	if !errors.Is(err, cause) {
+		t.Errorf("expected the cause to be found in the permanent error")
+	}
+	if err.Error() != cause.Error() {
+		t.Errorf("expected the message of the cause, got: %v", err)
+	}
+}
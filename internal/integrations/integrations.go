@@ -19,48 +19,184 @@ package integrations
 import (
 	"context"
 	"fmt"
-	"reflect"
+	"maps"
+	"slices"
 
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/integrations/alertmanager"
+	"freepik.com/notifik/internal/integrations/driver"
+	"freepik.com/notifik/internal/integrations/slack"
 	"freepik.com/notifik/internal/integrations/webhook"
-	integrationsRegistry "freepik.com/notifik/internal/registry/integrations"
 )
 
-// SendMessage send a message to a specific integration
-func SendMessage(ctx context.Context, integrationsReg *integrationsRegistry.IntegrationsRegistry, integrationName, msg string) (err error) {
+const (
+	//
+	DriverNotFoundErrorMessage = "integration type '%s' is not supported"
+)
 
-	integrationList := integrationsReg.GetIntegrations()
+var (
+	// drivers is a map of integration types and the drivers in charge of them.
+	// Implement other integrations in their own package and register them here
+	drivers = map[string]driver.Driver{
+		"webhook":      &webhook.Driver{},
+		"slack":        slack.NewDriver(),
+		"alertmanager": alertmanager.NewDriver(),
+	}
+)
 
-	//
-	integrationFound := false
-	for _, integObj := range integrationList {
+// GetDriver return the driver in charge of the provided integration type
+func GetDriver(integrationType string) (integrationDriver driver.Driver, exists bool) {
+	integrationDriver, exists = drivers[integrationType]
+	return integrationDriver, exists
+}
+
+// GetDriverTypes return the integration types having a driver, sorted by name
+func GetDriverTypes() []string {
+	driverTypes := slices.Collect(maps.Keys(drivers))
+	slices.Sort(driverTypes)
+	return driverTypes
+}
+
+// ValidateIntegration checks the Integration against the driver of its type
+func ValidateIntegration(integration *v1alpha1.Integration) (err error) {
+	integrationDriver, driverFound := GetDriver(integration.Spec.Type)
+	if !driverFound {
+		return fmt.Errorf(DriverNotFoundErrorMessage, integration.Spec.Type)
+	}
+
+	return integrationDriver.Validate(integration)
+}
+
+// CheckHealth asks the driver of the Integration whether its backend is reachable
+func CheckHealth(ctx context.Context, integration *v1alpha1.Integration) (err error) {
+	integrationDriver, driverFound := GetDriver(integration.Spec.Type)
+	if !driverFound {
+		return fmt.Errorf(DriverNotFoundErrorMessage, integration.Spec.Type)
+	}
+
+	return integrationDriver.Health(ctx, integration)
+}
+
+// SendMessage sends a message through the driver of the Integration
+func SendMessage(ctx context.Context, integration *v1alpha1.Integration, msg *driver.Message) (err error) {
+	integrationDriver, driverFound := GetDriver(integration.Spec.Type)
+	if !driverFound {
+		return driver.NewPermanentError(fmt.Errorf(DriverNotFoundErrorMessage, integration.Spec.Type))
+	}
+
+	return integrationDriver.SendMessage(ctx, integration, msg)
+}
+
+// IsResolver reports whether the driver of the Integration must be told when objects do not meet the conditions anymore
+func IsResolver(integration *v1alpha1.Integration) bool {
+	integrationDriver, driverFound := GetDriver(integration.Spec.Type)
+	if !driverFound {
+		return false
+	}
+
+	_, isResolver := integrationDriver.(driver.Resolver)
+	return isResolver
+}
+
+// IsTracking reports whether the driver of the Integration tracks the object of the message,
+// so it must be told when the object does not meet the conditions anymore
+func IsTracking(integration *v1alpha1.Integration, msg *driver.Message) bool {
+	integrationDriver, driverFound := GetDriver(integration.Spec.Type)
+	if !driverFound {
+		return false
+	}
+
+	resolver, isResolver := integrationDriver.(driver.Resolver)
+	if !isResolver {
+		return false
+	}
+
+	return resolver.Tracking(integration, msg)
+}
+
+// ResolveMessage tells the Integration that the object of the message does not meet the conditions anymore.
+// Only drivers tracking the objects that triggered messages are told, so it does nothing for the rest
+func ResolveMessage(ctx context.Context, integration *v1alpha1.Integration, msg *driver.Message) (err error) {
+	integrationDriver, driverFound := GetDriver(integration.Spec.Type)
+	if !driverFound {
+		return driver.NewPermanentError(fmt.Errorf(DriverNotFoundErrorMessage, integration.Spec.Type))
+	}
+
+	resolver, isResolver := integrationDriver.(driver.Resolver)
+	if !isResolver {
+		return nil
+	}
+
+	return resolver.ResolveMessage(ctx, integration, msg)
+}
+
+// RefreshMessages asks the driver of the Integration to send again the messages its backend would forget.
+// It does nothing for drivers that do not need it
+func RefreshMessages(ctx context.Context, integration *v1alpha1.Integration) (err error) {
+	integrationDriver, driverFound := GetDriver(integration.Spec.Type)
+	if !driverFound {
+		return nil
+	}
 
-		if integObj.Name != integrationName {
-			continue
-		}
-		integrationFound = true
+	refresher, isRefresher := integrationDriver.(driver.Refresher)
+	if !isRefresher {
+		return nil
+	}
 
-		//
-		if integObj.Spec.Type == "webhook" {
+	return refresher.RefreshMessages(ctx, integration)
+}
 
-			// TODO: Perform this check on config initialization, not here
-			if reflect.ValueOf(integObj.Spec.Webhook).IsZero() {
-				return fmt.Errorf("webhook configuration missing for integration %s", integrationName)
-			}
+// IsRefresher reports whether the driver of the Integration must send its messages again periodically
+func IsRefresher(integration *v1alpha1.Integration) bool {
+	integrationDriver, driverFound := GetDriver(integration.Spec.Type)
+	if !driverFound {
+		return false
+	}
 
-			err = webhook.SendMessage(ctx, &integObj.Spec.Webhook, msg)
-			if err != nil {
-				return err
-			}
-		}
+	_, isRefresher := integrationDriver.(driver.Refresher)
+	return isRefresher
+}
 
-		// Implement other integrations here
-		////////////////////////////////////
+// ForgetNotification tells the driver of the Integration to drop what it keeps about the messages of the Notification.
+// It does nothing for drivers that keep nothing
+func ForgetNotification(integration *v1alpha1.Integration, notification *v1alpha1.Notification) {
+	integrationDriver, driverFound := GetDriver(integration.Spec.Type)
+	if !driverFound {
+		return
+	}
 
+	forgetter, isForgetter := integrationDriver.(driver.Forgetter)
+	if !isForgetter {
+		return
 	}
 
-	if !integrationFound {
-		return fmt.Errorf("integration '%s' not found", integrationName)
+	forgetter.ForgetNotification(integration, notification)
+}
+
+// ForgetIntegration tells the driver of the Integration to drop what it keeps about all its messages.
+// It does nothing for drivers that keep nothing
+func ForgetIntegration(integration *v1alpha1.Integration) {
+	integrationDriver, driverFound := GetDriver(integration.Spec.Type)
+	if !driverFound {
+		return
+	}
+
+	forgetter, isForgetter := integrationDriver.(driver.Forgetter)
+	if !isForgetter {
+		return
+	}
+
+	forgetter.ForgetIntegration(integration)
+}
+
+// IsForgetter reports whether the driver of the Integration keeps state about the messages of each Notification
+func IsForgetter(integration *v1alpha1.Integration) bool {
+	integrationDriver, driverFound := GetDriver(integration.Spec.Type)
+	if !driverFound {
+		return false
 	}
 
-	return nil
+	_, isForgetter := integrationDriver.(driver.Forgetter)
+	return isForgetter
 }
@@ -0,0 +1,395 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	//
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/integrations/driver"
+	"freepik.com/notifik/internal/integrations/webhook"
+)
+
+const (
+	// apiBaseUrl is the URL where the methods of Slack API are served
+	apiBaseUrl = "https://slack.com/api/"
+
+	// apiRateLimitedError is the error replied by Slack API when too many messages are posted
+	apiRateLimitedError = "ratelimited"
+
+	//
+	ValidatorNotFoundErrorMessage    = "validator %s not found"
+	ValidationFailedErrorMessage     = "validation failed: %s"
+	ConfigurationMissingErrorMessage = "slack configuration missing for integration '%s'"
+	DestinationMissingErrorMessage   = "one of 'webhookUrl' or 'token' is required in slack configuration"
+	DestinationConflictErrorMessage  = "'webhookUrl' and 'token' can not be used at the same time in slack configuration"
+	ChannelMissingErrorMessage       = "a channel is required to post messages using a token"
+	HttpRequestCreationErrorMessage  = "error creating http request: %s"
+	HttpRequestSendingErrorMessage   = "error sending http request: %s"
+	HttpResponseErrorMessage         = "slack replied with status code %d: %s"
+	ApiResponseDecodingErrorMessage  = "error decoding response from slack API: %s"
+	ApiResponseErrorMessage          = "slack API replied with error: %s"
+)
+
+var (
+	// validatorsMap is a map of validator names and their respective validation functions
+	validatorsMap = map[string]func(data string) (result bool, hint string, err error){
+		"blockkit": ValidateBlockKit,
+	}
+)
+
+// Driver implements the delivery of messages to Slack
+type Driver struct {
+	mu sync.Mutex
+
+	// threads stores the timestamp of the first message posted for each object, indexed by
+	// integration, Notification, channel and UID of the object.
+	// It is used to post the following messages of the same object as replies, until the object is resolved
+	threads map[string]string
+}
+
+// NewDriver return a Slack driver ready to be used
+func NewDriver() *Driver {
+	return &Driver{
+		threads: make(map[string]string),
+	}
+}
+
+// Validate checks whether the Integration carries a usable Slack configuration
+func (d *Driver) Validate(integration *v1alpha1.Integration) (err error) {
+	params := &integration.Spec.Slack
+
+	if reflect.ValueOf(*params).IsZero() {
+		return fmt.Errorf(ConfigurationMissingErrorMessage, integration.Name)
+	}
+
+	if params.WebhookUrl == "" && params.Token == "" {
+		return errors.New(DestinationMissingErrorMessage)
+	}
+
+	if params.WebhookUrl != "" && params.Token != "" {
+		return errors.New(DestinationConflictErrorMessage)
+	}
+
+	if params.WebhookUrl != "" {
+		_, err = webhook.ParseUrl(params.WebhookUrl)
+		if err != nil {
+			return err
+		}
+	}
+
+	if params.Validator != "" {
+		if _, validatorFound := validatorsMap[params.Validator]; !validatorFound {
+			return fmt.Errorf(ValidatorNotFoundErrorMessage, params.Validator)
+		}
+	}
+
+	return nil
+}
+
+// SendMessage posts the message to Slack.
+// Messages whose data is not a JSON object are sent as plain text
+func (d *Driver) SendMessage(ctx context.Context, integration *v1alpha1.Integration, msg *driver.Message) (err error) {
+	params := &integration.Spec.Slack
+
+	// Check if the integration has a validator and execute it when available
+	if params.Validator != "" {
+		validatorFunc, validatorFound := validatorsMap[params.Validator]
+		if !validatorFound {
+			return driver.NewPermanentError(fmt.Errorf(ValidatorNotFoundErrorMessage, params.Validator))
+		}
+
+		// Retrying will not make the message valid
+		validatorResult, validatorHint, err := validatorFunc(msg.Data)
+		if err != nil {
+			return driver.NewPermanentError(fmt.Errorf(ValidationFailedErrorMessage, err.Error()))
+		}
+
+		if !validatorResult {
+			return driver.NewPermanentError(fmt.Errorf(ValidationFailedErrorMessage, validatorHint))
+		}
+	}
+
+	payload := map[string]interface{}{}
+	err = json.Unmarshal([]byte(msg.Data), &payload)
+	if err != nil {
+		payload = map[string]interface{}{"text": msg.Data}
+	}
+
+	channel := getChannel(integration, msg)
+	if channel != "" {
+		payload["channel"] = channel
+	}
+
+	// Incoming webhooks do not return anything useful, so threads are not possible there
+	if params.WebhookUrl != "" {
+		return d.postToWebhook(ctx, params, payload)
+	}
+
+	if channel == "" {
+		return driver.NewPermanentError(errors.New(ChannelMissingErrorMessage))
+	}
+
+	// Reply in the thread of the object when it already exists
+	threadKey := ""
+	if msg.Target.Slack.ThreadByObject {
+		threadKey = getThreadKey(integration, msg)
+
+		if threadTs, threadFound := d.getThread(threadKey); threadFound {
+			payload["thread_ts"] = threadTs
+		}
+	}
+
+	response, err := d.callApi(ctx, params, "chat.postMessage", payload)
+	if err != nil {
+		return err
+	}
+
+	if threadKey == "" {
+		return nil
+	}
+
+	// Deleted objects will not send more messages, so their threads are forgotten
+	if msg.EventType == watch.Deleted {
+		d.deleteThread(threadKey)
+		return nil
+	}
+
+	if _, threadFound := d.getThread(threadKey); !threadFound {
+		d.setThread(threadKey, response.Ts)
+	}
+
+	return nil
+}
+
+// Tracking reports whether a thread is open for the object of the message
+func (d *Driver) Tracking(integration *v1alpha1.Integration, msg *driver.Message) bool {
+	if !msg.Target.Slack.ThreadByObject {
+		return false
+	}
+
+	_, threadFound := d.getThread(getThreadKey(integration, msg))
+	return threadFound
+}
+
+// ResolveMessage forgets the thread of the object, so next messages of the object start a new one.
+// Nothing is posted, as Slack messages can not be resolved
+func (d *Driver) ResolveMessage(ctx context.Context, integration *v1alpha1.Integration, msg *driver.Message) (err error) {
+	d.deleteThread(getThreadKey(integration, msg))
+	return nil
+}
+
+// ForgetNotification forgets the threads opened for the objects of the Notification through the Integration
+func (d *Driver) ForgetNotification(integration *v1alpha1.Integration, notification *v1alpha1.Notification) {
+	d.deleteThreads(strings.Join([]string{
+		getIntegrationKey(integration), notification.Namespace, notification.Name, "",
+	}, "/"))
+}
+
+// ForgetIntegration forgets all the threads opened through the Integration
+func (d *Driver) ForgetIntegration(integration *v1alpha1.Integration) {
+	d.deleteThreads(getIntegrationKey(integration) + "/")
+}
+
+// Health checks whether Slack accepts the token, or the host of the incoming webhook accepts connections
+func (d *Driver) Health(ctx context.Context, integration *v1alpha1.Integration) (err error) {
+	params := &integration.Spec.Slack
+
+	if params.WebhookUrl != "" {
+		return webhook.CheckUrlReachable(ctx, params.WebhookUrl)
+	}
+
+	_, err = d.callApi(ctx, params, "auth.test", map[string]interface{}{})
+	return err
+}
+
+// ValidatorExists checks whether a validator with the provided name is available for Slack
+func ValidatorExists(name string) bool {
+	_, validatorFound := validatorsMap[name]
+	return validatorFound
+}
+
+// postToWebhook sends the payload to the Slack incoming webhook of the Integration
+func (d *Driver) postToWebhook(ctx context.Context, params *v1alpha1.IntegrationSlack, payload map[string]interface{}) (err error) {
+	responseBody, statusCode, err := d.post(ctx, params, params.WebhookUrl, payload)
+	if err != nil {
+		return err
+	}
+
+	if statusCode != http.StatusOK {
+		return newHttpResponseError(statusCode, responseBody)
+	}
+
+	return nil
+}
+
+// callApi calls a method of Slack API using the token of the Integration
+func (d *Driver) callApi(ctx context.Context, params *v1alpha1.IntegrationSlack, method string, payload map[string]interface{}) (response *PostMessageResponse, err error) {
+	responseBody, statusCode, err := d.post(ctx, params, apiBaseUrl+method, payload)
+	if err != nil {
+		return response, err
+	}
+
+	if statusCode != http.StatusOK {
+		return response, newHttpResponseError(statusCode, responseBody)
+	}
+
+	// Slack API replies 200 even for failed calls, so the body must be checked
+	response = &PostMessageResponse{}
+	err = json.Unmarshal(responseBody, response)
+	if err != nil {
+		return response, fmt.Errorf(ApiResponseDecodingErrorMessage, err)
+	}
+
+	if !response.Ok {
+		return response, newApiResponseError(response.Error)
+	}
+
+	return response, nil
+}
+
+// newApiResponseError return the error for a failed call to Slack API.
+// Errors such as 'invalid_auth' or 'channel_not_found' will not be solved by retrying, unlike rate limits
+func newApiResponseError(apiError string) error {
+	err := fmt.Errorf(ApiResponseErrorMessage, apiError)
+
+	if apiError != apiRateLimitedError {
+		return driver.NewPermanentError(err)
+	}
+	return err
+}
+
+// newHttpResponseError return the error for a response of Slack that was not successful.
+// Only rate limits and server errors are retried
+func newHttpResponseError(statusCode int, body []byte) error {
+	err := fmt.Errorf(HttpResponseErrorMessage, statusCode, driver.TruncateResponseBody(body))
+
+	if statusCode != http.StatusTooManyRequests && statusCode < http.StatusInternalServerError {
+		return driver.NewPermanentError(err)
+	}
+	return err
+}
+
+// post sends the payload as JSON to the provided URL, and returns the body of the response
+func (d *Driver) post(ctx context.Context, params *v1alpha1.IntegrationSlack, url string, payload map[string]interface{}) (responseBody []byte, statusCode int, err error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return responseBody, statusCode, fmt.Errorf(HttpRequestCreationErrorMessage, err)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return responseBody, statusCode, fmt.Errorf(HttpRequestCreationErrorMessage, err)
+	}
+
+	httpRequest.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if params.Token != "" {
+		httpRequest.Header.Set("Authorization", "Bearer "+params.Token)
+	}
+
+	httpClient := driver.NewHttpClient(params.Timeout)
+	httpResponse, err := httpClient.Do(httpRequest)
+	if err != nil {
+		return responseBody, statusCode, fmt.Errorf(HttpRequestSendingErrorMessage, err)
+	}
+	defer httpResponse.Body.Close()
+
+	responseBody, err = io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return responseBody, httpResponse.StatusCode, fmt.Errorf(HttpRequestSendingErrorMessage, err)
+	}
+
+	return responseBody, httpResponse.StatusCode, nil
+}
+
+// getChannel return the channel where the message is posted. Notifications can override the one of the Integration
+func getChannel(integration *v1alpha1.Integration, msg *driver.Message) string {
+	if msg.Target.Slack.Channel != "" {
+		return msg.Target.Slack.Channel
+	}
+	return integration.Spec.Slack.Channel
+}
+
+// getThreadKey return the key used to index the thread of an object in the threads
+func getThreadKey(integration *v1alpha1.Integration, msg *driver.Message) string {
+	objectUid := string((&unstructured.Unstructured{Object: msg.Object}).GetUID())
+
+	return strings.Join([]string{
+		getIntegrationKey(integration), msg.Notification.Namespace, msg.Notification.Name,
+		getChannel(integration, msg), objectUid,
+	}, "/")
+}
+
+// getIntegrationKey return the part of the thread keys identifying the Integration.
+// ClusterIntegrations are prefixed with their kind, as they can have the same name as Integrations
+func getIntegrationKey(integration *v1alpha1.Integration) string {
+	if integration.Kind == v1alpha1.ClusterIntegrationKind {
+		return integration.Kind + "/" + integration.Name
+	}
+	return integration.Name
+}
+
+// getThread return the timestamp of the thread stored under the provided key
+func (d *Driver) getThread(key string) (threadTs string, exists bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	threadTs, exists = d.threads[key]
+	return threadTs, exists
+}
+
+// setThread stores the timestamp of a thread under the provided key
+func (d *Driver) setThread(key, threadTs string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.threads[key] = threadTs
+}
+
+// deleteThread forgets the thread stored under the provided key
+func (d *Driver) deleteThread(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.threads, key)
+}
+
+// deleteThreads forgets the threads stored under keys with the provided prefix
+func (d *Driver) deleteThreads(keyPrefix string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for key := range d.threads {
+		if strings.HasPrefix(key, keyPrefix) {
+			delete(d.threads, key)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	//
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/integrations/driver"
+)
+
+func TestValidateBlockKit(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantResult bool
+		wantHint   string
+		wantErr    bool
+	}{
+		{
+			name:       "valid section and header blocks",
+			data:       `{"blocks": [{"type": "header", "text": {"type": "plain_text", "text": "Pod failed"}}, {"type": "section", "text": {"type": "mrkdwn", "text": "*default/example*"}}]}`,
+			wantResult: true,
+		},
+		{
+			name:    "data is not JSON",
+			data:    "Pod failed",
+			wantErr: true,
+		},
+		{
+			name:     "blocks are missing",
+			data:     `{"text": "Pod failed"}`,
+			wantHint: "field 'blocks' not found or empty",
+		},
+		{
+			name:     "too many blocks",
+			data:     `{"blocks": [` + strings.Repeat(`{"type": "divider"},`, blockKitMaxBlocks) + `{"type": "divider"}]}`,
+			wantHint: fmt.Sprintf("more than %d blocks found", blockKitMaxBlocks),
+		},
+		{
+			name:     "unknown block type",
+			data:     `{"blocks": [{"type": "table"}]}`,
+			wantHint: "block 0: unknown block type 'table'",
+		},
+		{
+			name:     "block_id too long",
+			data:     `{"blocks": [{"type": "divider", "block_id": "` + strings.Repeat("a", blockKitMaxBlockIdLength+1) + `"}]}`,
+			wantHint: "field 'block_id' is longer than",
+		},
+		{
+			name:     "section without text nor fields",
+			data:     `{"blocks": [{"type": "section"}]}`,
+			wantHint: "section blocks require 'text' or 'fields'",
+		},
+		{
+			name:     "section with an unknown text type",
+			data:     `{"blocks": [{"type": "section", "text": {"type": "html", "text": "<b>failed</b>"}}]}`,
+			wantHint: "unknown text object type 'html'",
+		},
+		{
+			name:     "section with an empty field",
+			data:     `{"blocks": [{"type": "section", "fields": [{"type": "mrkdwn", "text": ""}]}]}`,
+			wantHint: "text objects require a non-empty 'text'",
+		},
+		{
+			name:     "header with markdown text",
+			data:     `{"blocks": [{"type": "header", "text": {"type": "mrkdwn", "text": "*failed*"}}]}`,
+			wantHint: "header blocks require a 'plain_text' text object",
+		},
+		{
+			name:     "header text too long",
+			data:     `{"blocks": [{"type": "header", "text": {"type": "plain_text", "text": "` + strings.Repeat("a", blockKitMaxHeaderLength+1) + `"}}]}`,
+			wantHint: "header text is longer than",
+		},
+		{
+			name:     "context without elements",
+			data:     `{"blocks": [{"type": "context", "elements": []}]}`,
+			wantHint: "context blocks require between 1 and",
+		},
+		{
+			name:     "image without alt text",
+			data:     `{"blocks": [{"type": "image", "image_url": "https://example.com/image.png"}]}`,
+			wantHint: "image blocks require 'image_url' and 'alt_text'",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, hint, err := ValidateBlockKit(test.data)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("expected error %v, got: %v", test.wantErr, err)
+			}
+			if result != test.wantResult {
+				t.Errorf("expected result %v, got %v (hint: %s)", test.wantResult, result, hint)
+			}
+			if !strings.Contains(hint, test.wantHint) {
+				t.Errorf("expected hint containing %q, got %q", test.wantHint, hint)
+			}
+		})
+	}
+}
+
+func TestNewHttpResponseError(t *testing.T) {
+	tests := []struct {
+		statusCode    int
+		wantPermanent bool
+	}{
+		{statusCode: http.StatusBadRequest, wantPermanent: true},
+		{statusCode: http.StatusForbidden, wantPermanent: true},
+		{statusCode: http.StatusNotFound, wantPermanent: true},
+		{statusCode: http.StatusRequestTimeout, wantPermanent: true},
+		{statusCode: http.StatusTooManyRequests, wantPermanent: false},
+		{statusCode: http.StatusInternalServerError, wantPermanent: false},
+		{statusCode: http.StatusServiceUnavailable, wantPermanent: false},
+	}
+
+	for _, test := range tests {
+		t.Run(http.StatusText(test.statusCode), func(t *testing.T) {
+			err := newHttpResponseError(test.statusCode, []byte("invalid_payload"))
+
+			permanentError := &driver.PermanentError{}
+			if errors.As(err, &permanentError) != test.wantPermanent {
+				t.Errorf("expected permanent %v, got error: %v", test.wantPermanent, err)
+			}
+			if !strings.Contains(err.Error(), "invalid_payload") {
+				t.Errorf("expected the body in the error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewApiResponseError(t *testing.T) {
+	tests := []struct {
+		apiError      string
+		wantPermanent bool
+	}{
+		{apiError: "invalid_auth", wantPermanent: true},
+		{apiError: "channel_not_found", wantPermanent: true},
+		{apiError: "not_in_channel", wantPermanent: true},
+		{apiError: apiRateLimitedError, wantPermanent: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.apiError, func(t *testing.T) {
+			err := newApiResponseError(test.apiError)
+
+			permanentError := &driver.PermanentError{}
+			if errors.As(err, &permanentError) != test.wantPermanent {
+				t.Errorf("expected permanent %v, got error: %v", test.wantPermanent, err)
+			}
+		})
+	}
+}
+
+func TestDriverForgetsThreads(t *testing.T) {
+	integration := &v1alpha1.Integration{
+		ObjectMeta: metav1.ObjectMeta{Name: "slack"},
+		Spec: v1alpha1.IntegrationSpec{
+			Type:  "slack",
+			Slack: v1alpha1.IntegrationSlack{Token: "xoxb-token", Channel: "#alerts"},
+		},
+	}
+	clusterIntegration := integration.DeepCopy()
+	clusterIntegration.Kind = v1alpha1.ClusterIntegrationKind
+
+	notification := &v1alpha1.Notification{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "failed-pods"},
+	}
+	otherNotification := &v1alpha1.Notification{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "failed-pods-2"},
+	}
+
+	newMessage := func(notification *v1alpha1.Notification, objectUid string) *driver.Message {
+		return &driver.Message{
+			Notification: notification,
+			Target:       v1alpha1.NotificationMessage{Slack: v1alpha1.NotificationSlack{ThreadByObject: true}},
+			Object:       map[string]interface{}{"metadata": map[string]interface{}{"uid": objectUid}},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		forget       func(d *Driver)
+		wantTracking map[string]bool
+	}{
+		{
+			name: "resolved objects",
+			forget: func(d *Driver) {
+				_ = d.ResolveMessage(context.Background(), integration, newMessage(notification, "1234"))
+			},
+			wantTracking: map[string]bool{
+				"integration/notification/1234":       false,
+				"integration/notification/5678":       true,
+				"integration/other-notification/1234": true,
+				"cluster-integration/notification":    true,
+			},
+		},
+		{
+			name: "removed notifications",
+			forget: func(d *Driver) {
+				d.ForgetNotification(integration, notification)
+			},
+			wantTracking: map[string]bool{
+				"integration/notification/1234":       false,
+				"integration/notification/5678":       false,
+				"integration/other-notification/1234": true,
+				"cluster-integration/notification":    true,
+			},
+		},
+		{
+			name: "removed integrations",
+			forget: func(d *Driver) {
+				d.ForgetIntegration(integration)
+			},
+			wantTracking: map[string]bool{
+				"integration/notification/1234":       false,
+				"integration/notification/5678":       false,
+				"integration/other-notification/1234": false,
+				"cluster-integration/notification":    true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			threads := map[string]struct {
+				integration *v1alpha1.Integration
+				message     *driver.Message
+			}{
+				"integration/notification/1234":       {integration, newMessage(notification, "1234")},
+				"integration/notification/5678":       {integration, newMessage(notification, "5678")},
+				"integration/other-notification/1234": {integration, newMessage(otherNotification, "1234")},
+				"cluster-integration/notification":    {clusterIntegration, newMessage(notification, "1234")},
+			}
+
+			d := NewDriver()
+			for _, thread := range threads {
+				d.setThread(getThreadKey(thread.integration, thread.message), "1700000000.000100")
+			}
+
+			test.forget(d)
+
+			for threadName, thread := range threads {
+				if tracking := d.Tracking(thread.integration, thread.message); tracking != test.wantTracking[threadName] {
+					t.Errorf("expected tracking of thread '%s' to be %v, got %v",
+						threadName, test.wantTracking[threadName], tracking)
+				}
+			}
+		})
+	}
+}
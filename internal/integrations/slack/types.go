@@ -0,0 +1,57 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slack
+
+// BlockKitMessage represents the parts of a Slack message that are checked by the Block Kit validator
+// Ref: https://api.slack.com/reference/block-kit/blocks
+type BlockKitMessage struct {
+	Text   string          `json:"text,omitempty"`
+	Blocks []BlockKitBlock `json:"blocks"`
+}
+
+// BlockKitBlock represents a layout block of a Slack message
+type BlockKitBlock struct {
+	Type    string `json:"type"`
+	BlockId string `json:"block_id,omitempty"`
+
+	// Fields used by 'section' and 'header' blocks
+	Text   *BlockKitTextObject  `json:"text,omitempty"`
+	Fields []BlockKitTextObject `json:"fields,omitempty"`
+
+	// Fields used by 'context' and 'actions' blocks
+	Elements []map[string]interface{} `json:"elements,omitempty"`
+
+	// Fields used by 'image' blocks
+	ImageUrl string `json:"image_url,omitempty"`
+	AltText  string `json:"alt_text,omitempty"`
+}
+
+// BlockKitTextObject represents a text object inside a block
+// Ref: https://api.slack.com/reference/block-kit/composition-objects#text
+type BlockKitTextObject struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// PostMessageResponse represents the response of chat.postMessage and auth.test methods of Slack API
+// Ref: https://api.slack.com/methods/chat.postMessage
+type PostMessageResponse struct {
+	Ok      bool   `json:"ok"`
+	Error   string `json:"error,omitempty"`
+	Channel string `json:"channel,omitempty"`
+	Ts      string `json:"ts,omitempty"`
+}
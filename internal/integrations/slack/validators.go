@@ -0,0 +1,149 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+const (
+	blockKitDataUnmarshalErrorMessage         = "error decoding JSON from 'message.data' for Block Kit validator: %s"
+	blockKitDataRequiredStructureErrorMessage = "notification field 'message.data' does not meet the syntax requirements for Block Kit"
+
+	// Limits defined by Slack
+	// Ref: https://api.slack.com/reference/block-kit/blocks
+	blockKitMaxBlocks          = 50
+	blockKitMaxBlockIdLength   = 255
+	blockKitMaxSectionFields   = 10
+	blockKitMaxHeaderLength    = 150
+	blockKitMaxContextElements = 10
+	blockKitMaxActionsElements = 25
+)
+
+var (
+	// blockKitBlockTypes is the list of layout blocks that can be used in messages
+	blockKitBlockTypes = []string{
+		"actions", "context", "divider", "file", "header", "image", "input", "rich_text", "section", "video",
+	}
+
+	// blockKitTextTypes is the list of types for text objects
+	blockKitTextTypes = []string{"plain_text", "mrkdwn"}
+)
+
+// ValidateBlockKit checks whether the notification data meets the requirements for Block Kit messages
+func ValidateBlockKit(data string) (result bool, hint string, err error) {
+
+	message := BlockKitMessage{}
+
+	//
+	err = json.Unmarshal([]byte(data), &message)
+	if err != nil {
+		return false, hint, fmt.Errorf(blockKitDataUnmarshalErrorMessage, err)
+	}
+
+	if len(message.Blocks) == 0 {
+		return false, fmt.Sprintf("%s: %s", blockKitDataRequiredStructureErrorMessage, "field 'blocks' not found or empty"), nil
+	}
+
+	if len(message.Blocks) > blockKitMaxBlocks {
+		hint = fmt.Sprintf("%s: more than %d blocks found", blockKitDataRequiredStructureErrorMessage, blockKitMaxBlocks)
+		return false, hint, nil
+	}
+
+	//
+	for blockIndex, block := range message.Blocks {
+		blockHint := validateBlock(&block)
+		if blockHint != "" {
+			hint = fmt.Sprintf("%s: block %d: %s", blockKitDataRequiredStructureErrorMessage, blockIndex, blockHint)
+			return false, hint, nil
+		}
+	}
+
+	return true, hint, nil
+}
+
+// validateBlock checks a single block and returns a hint about the problem when it is not valid
+func validateBlock(block *BlockKitBlock) (hint string) {
+
+	if !slices.Contains(blockKitBlockTypes, block.Type) {
+		return fmt.Sprintf("unknown block type '%s'", block.Type)
+	}
+
+	if len(block.BlockId) > blockKitMaxBlockIdLength {
+		return fmt.Sprintf("field 'block_id' is longer than %d characters", blockKitMaxBlockIdLength)
+	}
+
+	switch block.Type {
+	case "section":
+		if block.Text == nil && len(block.Fields) == 0 {
+			return "section blocks require 'text' or 'fields'"
+		}
+		if block.Text != nil {
+			if textHint := validateTextObject(block.Text); textHint != "" {
+				return textHint
+			}
+		}
+		if len(block.Fields) > blockKitMaxSectionFields {
+			return fmt.Sprintf("section blocks admit up to %d fields", blockKitMaxSectionFields)
+		}
+		for _, field := range block.Fields {
+			if textHint := validateTextObject(&field); textHint != "" {
+				return textHint
+			}
+		}
+
+	case "header":
+		if block.Text == nil || block.Text.Type != "plain_text" {
+			return "header blocks require a 'plain_text' text object"
+		}
+		if len(block.Text.Text) > blockKitMaxHeaderLength {
+			return fmt.Sprintf("header text is longer than %d characters", blockKitMaxHeaderLength)
+		}
+
+	case "context":
+		if len(block.Elements) == 0 || len(block.Elements) > blockKitMaxContextElements {
+			return fmt.Sprintf("context blocks require between 1 and %d elements", blockKitMaxContextElements)
+		}
+
+	case "actions":
+		if len(block.Elements) == 0 || len(block.Elements) > blockKitMaxActionsElements {
+			return fmt.Sprintf("actions blocks require between 1 and %d elements", blockKitMaxActionsElements)
+		}
+
+	case "image":
+		if block.ImageUrl == "" || block.AltText == "" {
+			return "image blocks require 'image_url' and 'alt_text'"
+		}
+	}
+
+	return ""
+}
+
+// validateTextObject checks a text object and returns a hint about the problem when it is not valid
+func validateTextObject(textObject *BlockKitTextObject) (hint string) {
+	if !slices.Contains(blockKitTextTypes, textObject.Type) {
+		return fmt.Sprintf("unknown text object type '%s'", textObject.Type)
+	}
+
+	if textObject.Text == "" {
+		return "text objects require a non-empty 'text'"
+	}
+
+	return ""
+}
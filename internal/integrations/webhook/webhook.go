@@ -21,10 +21,16 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"time"
 
 	//
 	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/integrations/driver"
 )
 
 const (
@@ -36,6 +42,15 @@ const (
 	ValidationFailedErrorMessage    = "validation failed: %s"
 	HttpRequestCreationErrorMessage = "error creating http request: %s"
 	HttpRequestSendingErrorMessage  = "error sending http request: %s"
+	HttpResponseErrorMessage        = "webhook replied with status code %d: %s"
+
+	ConfigurationMissingErrorMessage = "webhook configuration missing for integration '%s'"
+	UrlParsingErrorMessage           = "error parsing webhook url: %s"
+	UrlNotAbsoluteErrorMessage       = "webhook url must be absolute, including scheme and host"
+	HostUnreachableErrorMessage      = "webhook host is unreachable: %s"
+
+	// healthCheckTimeout is the maximum time to wait for the webhook host to accept a connection
+	healthCheckTimeout = 5 * time.Second
 )
 
 var (
@@ -45,6 +60,87 @@ var (
 	}
 )
 
+// Driver implements the delivery of messages through HTTP webhooks
+type Driver struct{}
+
+// Validate checks whether the Integration carries a usable webhook configuration
+func (d *Driver) Validate(integration *v1alpha1.Integration) (err error) {
+
+	if reflect.ValueOf(integration.Spec.Webhook).IsZero() {
+		return fmt.Errorf(ConfigurationMissingErrorMessage, integration.Name)
+	}
+
+	_, err = ParseUrl(integration.Spec.Webhook.Url)
+	if err != nil {
+		return err
+	}
+
+	if integration.Spec.Webhook.Validator != "" {
+		if _, validatorFound := validatorsMap[integration.Spec.Webhook.Validator]; !validatorFound {
+			return fmt.Errorf(ValidatorNotFoundErrorMessage, integration.Spec.Webhook.Validator)
+		}
+	}
+
+	return nil
+}
+
+// SendMessage delivers the message to the webhook configured in the Integration
+func (d *Driver) SendMessage(ctx context.Context, integration *v1alpha1.Integration, msg *driver.Message) (err error) {
+	return SendMessage(ctx, &integration.Spec.Webhook, msg.Data)
+}
+
+// Health checks whether the host of the webhook accepts connections.
+// Nothing is sent to avoid side effects on the receiver
+func (d *Driver) Health(ctx context.Context, integration *v1alpha1.Integration) (err error) {
+	return CheckUrlReachable(ctx, integration.Spec.Webhook.Url)
+}
+
+// CheckUrlReachable checks whether the host of an URL accepts TCP connections
+func CheckUrlReachable(ctx context.Context, rawUrl string) (err error) {
+
+	parsedUrl, err := ParseUrl(rawUrl)
+	if err != nil {
+		return err
+	}
+
+	port := parsedUrl.Port()
+	if port == "" {
+		port = "80"
+		if parsedUrl.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	dialer := &net.Dialer{Timeout: healthCheckTimeout}
+	connection, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(parsedUrl.Hostname(), port))
+	if err != nil {
+		return fmt.Errorf(HostUnreachableErrorMessage, err)
+	}
+	_ = connection.Close()
+
+	return nil
+}
+
+// ValidatorExists checks whether a validator with the provided name is available for webhooks
+func ValidatorExists(name string) bool {
+	_, validatorFound := validatorsMap[name]
+	return validatorFound
+}
+
+// ParseUrl parses the URL of a webhook and checks it can be requested
+func ParseUrl(rawUrl string) (parsedUrl *url.URL, err error) {
+	parsedUrl, err = url.Parse(rawUrl)
+	if err != nil {
+		return parsedUrl, fmt.Errorf(UrlParsingErrorMessage, err)
+	}
+
+	if parsedUrl.Scheme == "" || parsedUrl.Host == "" {
+		return parsedUrl, fmt.Errorf(UrlParsingErrorMessage, UrlNotAbsoluteErrorMessage)
+	}
+
+	return parsedUrl, nil
+}
+
 func SendMessage(ctx context.Context, params *v1alpha1.IntegrationWebhook, data string) (err error) {
 
 	// Check if the webhook has a validator and execute it when available
@@ -52,24 +148,24 @@ func SendMessage(ctx context.Context, params *v1alpha1.IntegrationWebhook, data
 
 		_, validatorFound := validatorsMap[params.Validator]
 		if !validatorFound {
-			return fmt.Errorf(ValidatorNotFoundErrorMessage, params.Validator)
+			return driver.NewPermanentError(fmt.Errorf(ValidatorNotFoundErrorMessage, params.Validator))
 		}
 
-		//
+		// Retrying will not make the message valid
 		validatorResult, validatorHint, err := validatorsMap[params.Validator](data)
 		if err != nil {
-			return fmt.Errorf(ValidationFailedErrorMessage, err.Error())
+			return driver.NewPermanentError(fmt.Errorf(ValidationFailedErrorMessage, err.Error()))
 		}
 
 		if !validatorResult {
-			return fmt.Errorf(ValidationFailedErrorMessage, validatorHint)
+			return driver.NewPermanentError(fmt.Errorf(ValidationFailedErrorMessage, validatorHint))
 		}
 	}
 
-	httpClient := &http.Client{}
+	httpClient := driver.NewHttpClient(params.Timeout)
 
 	// Create the request
-	httpRequest, err := http.NewRequest(params.Verb, params.Url, nil)
+	httpRequest, err := http.NewRequestWithContext(ctx, params.Verb, params.Url, nil)
 	if err != nil {
 		return fmt.Errorf(HttpRequestCreationErrorMessage, err)
 	}
@@ -92,6 +188,20 @@ func SendMessage(ctx context.Context, params *v1alpha1.IntegrationWebhook, data
 	}
 	defer httpResponse.Body.Close()
 
-	//
-	return err
+	// Receivers can reply anything, so only the beginning of the body is kept for the error
+	if !isSuccessStatusCode(params, httpResponse.StatusCode) {
+		responseBody, _ := io.ReadAll(io.LimitReader(httpResponse.Body, driver.MaxResponseBodyLength+1))
+		return driver.NewHttpResponseError(HttpResponseErrorMessage, httpResponse.StatusCode, responseBody)
+	}
+
+	return nil
+}
+
+// isSuccessStatusCode checks whether the status code of the response means the message was delivered
+func isSuccessStatusCode(params *v1alpha1.IntegrationWebhook, statusCode int) bool {
+	if len(params.SuccessStatusCodes) == 0 {
+		return statusCode >= 200 && statusCode < 300
+	}
+
+	return slices.Contains(params.SuccessStatusCodes, statusCode)
 }
@@ -0,0 +1,101 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/integrations/driver"
+)
+
+func TestIsSuccessStatusCode(t *testing.T) {
+	tests := []struct {
+		name               string
+		successStatusCodes []int
+		statusCode         int
+		want               bool
+	}{
+		{name: "200 is a success by default", statusCode: http.StatusOK, want: true},
+		{name: "202 is a success by default", statusCode: http.StatusAccepted, want: true},
+		{name: "204 is a success by default", statusCode: http.StatusNoContent, want: true},
+		{name: "3xx is not a success by default", statusCode: http.StatusMovedPermanently, want: false},
+		{name: "4xx is not a success by default", statusCode: http.StatusBadRequest, want: false},
+		{name: "5xx is not a success by default", statusCode: http.StatusInternalServerError, want: false},
+		{
+			name:               "configured codes are a success",
+			successStatusCodes: []int{http.StatusOK, http.StatusConflict},
+			statusCode:         http.StatusConflict,
+			want:               true,
+		},
+		{
+			name:               "2xx not configured is not a success",
+			successStatusCodes: []int{http.StatusOK},
+			statusCode:         http.StatusAccepted,
+			want:               false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := &v1alpha1.IntegrationWebhook{SuccessStatusCodes: tt.successStatusCodes}
+			if got := isSuccessStatusCode(params, tt.statusCode); got != tt.want {
+				t.Errorf("expected %v for status code %d, got %v", tt.want, tt.statusCode, got)
+			}
+		})
+	}
+}
+
+func TestSendMessageClassifiesResponses(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		wantErr       bool
+		wantPermanent bool
+	}{
+		{name: "2xx is delivered", statusCode: http.StatusAccepted},
+		{name: "429 is retried", statusCode: http.StatusTooManyRequests, wantErr: true},
+		{name: "5xx is retried", statusCode: http.StatusBadGateway, wantErr: true},
+		{name: "4xx is permanent", statusCode: http.StatusNotFound, wantErr: true, wantPermanent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+
+			params := &v1alpha1.IntegrationWebhook{Url: server.URL, Verb: http.MethodPost}
+			err := SendMessage(context.Background(), params, "{}")
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got: %v", tt.wantErr, err)
+			}
+
+			permanentError := &driver.PermanentError{}
+			if errors.As(err, &permanentError) != tt.wantPermanent {
+				t.Errorf("expected permanent %v, got error: %v", tt.wantPermanent, err)
+			}
+		})
+	}
+}
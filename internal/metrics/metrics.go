@@ -0,0 +1,122 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	//
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// namespace is the prefix of all the metrics exposed by notifik
+	namespace = "notifik"
+
+	// Values for the labels describing results
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+	ResultMet     = "met"
+	ResultNotMet  = "not_met"
+
+	// Values for the label describing where a template failed
+	TemplateStageCondition   = "condition"
+	TemplateStageMessage     = "message"
+	TemplateStageFingerprint = "fingerprint"
+	TemplateStageGroup       = "group"
+
+	// Values for the label describing why a message was suppressed
+	SuppressionDuplicate   = "duplicate"
+	SuppressionRateLimited = "rate_limited"
+	SuppressionSilenced    = "silenced"
+)
+
+var (
+	// WatcherEvents counts the events received by the informers of the watched resource types
+	WatcherEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "watcher_events_total",
+		Help:      "Number of events received from watched resources",
+	}, []string{"resource_type", "event_type"})
+
+	// ConditionEvaluations counts the times the conditions of a Notification are evaluated, by result
+	ConditionEvaluations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notification_condition_evaluations_total",
+		Help:      "Number of evaluations of the conditions of Notifications",
+	}, []string{"notification", "result"})
+
+	// TemplateErrors counts the failures rendering the templates of a Notification
+	TemplateErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notification_template_errors_total",
+		Help:      "Number of failures rendering the templates of Notifications",
+	}, []string{"notification", "stage"})
+
+	// SuppressedMessages counts the messages of a Notification not sent due to its throttling, by reason
+	SuppressedMessages = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notification_suppressed_messages_total",
+		Help:      "Number of messages of Notifications suppressed by throttling",
+	}, []string{"notification", "reason"})
+
+	// DeliveryAttempts counts the attempts to deliver messages to an Integration, by result
+	DeliveryAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "delivery_attempts_total",
+		Help:      "Number of attempts to deliver messages to Integrations",
+	}, []string{"integration", "result"})
+
+	// DeliveryDiscarded counts the messages discarded after failing all the attempts, or failing permanently
+	DeliveryDiscarded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "delivery_discarded_total",
+		Help:      "Number of messages that could not be delivered to Integrations",
+	}, []string{"integration"})
+
+	// DeliveryDuration measures the time spent by each attempt to deliver a message
+	DeliveryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "delivery_duration_seconds",
+		Help:      "Duration of the attempts to deliver messages to Integrations",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"integration"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		WatcherEvents,
+		ConditionEvaluations,
+		TemplateErrors,
+		SuppressedMessages,
+		DeliveryAttempts,
+		DeliveryDiscarded,
+		DeliveryDuration,
+	)
+}
+
+// ObserveDeliveryAttempt records the result and the duration of an attempt to deliver a message
+func ObserveDeliveryAttempt(integrationName string, startTime time.Time, err error) {
+	result := ResultSuccess
+	if err != nil {
+		result = ResultFailure
+	}
+
+	DeliveryAttempts.WithLabelValues(integrationName, result).Inc()
+	DeliveryDuration.WithLabelValues(integrationName).Observe(time.Since(startTime).Seconds())
+}
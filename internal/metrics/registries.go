@@ -0,0 +1,85 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	//
+	sourcesRegistry "freepik.com/notifik/internal/registry/sources"
+	watchersRegistry "freepik.com/notifik/internal/registry/watchers"
+)
+
+var (
+	watchersActiveDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "watchers_active"),
+		"Number of started watchers for the resource types used by Notifications",
+		nil, nil)
+
+	sourcesInformersActiveDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "sources_informers_active"),
+		"Number of started informers for the extra resources used by Notifications",
+		nil, nil)
+
+	sourcesPoolItemsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "sources_pool_items"),
+		"Number of objects stored in the pool of each informer of extra resources",
+		[]string{"resource_type"}, nil)
+)
+
+// RegistriesCollector exposes the state of the registries. It is read on each scrape,
+// so registries do not need to know about metrics
+type RegistriesCollector struct {
+	WatchersRegistry *watchersRegistry.WatchersRegistry
+	SourcesRegistry  *sourcesRegistry.SourcesRegistry
+}
+
+func NewRegistriesCollector(watchersReg *watchersRegistry.WatchersRegistry, sourcesReg *sourcesRegistry.SourcesRegistry) *RegistriesCollector {
+	return &RegistriesCollector{
+		WatchersRegistry: watchersReg,
+		SourcesRegistry:  sourcesReg,
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *RegistriesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- watchersActiveDesc
+	ch <- sourcesInformersActiveDesc
+	ch <- sourcesPoolItemsDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *RegistriesCollector) Collect(ch chan<- prometheus.Metric) {
+	watchersActive := 0
+	for _, resourceType := range c.WatchersRegistry.GetRegisteredResourceTypes() {
+		if c.WatchersRegistry.IsStarted(resourceType) {
+			watchersActive++
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(watchersActiveDesc, prometheus.GaugeValue, float64(watchersActive))
+
+	informersActive := 0
+	for _, resourceType := range c.SourcesRegistry.GetRegisteredResourceTypes() {
+		if c.SourcesRegistry.IsStarted(resourceType) {
+			informersActive++
+		}
+
+		ch <- prometheus.MustNewConstMetric(sourcesPoolItemsDesc, prometheus.GaugeValue,
+			float64(len(c.SourcesRegistry.GetResources(resourceType))), resourceType)
+	}
+	ch <- prometheus.MustNewConstMetric(sourcesInformersActiveDesc, prometheus.GaugeValue, float64(informersActive))
+}
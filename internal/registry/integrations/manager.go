@@ -17,6 +17,9 @@ limitations under the License.
 package integrations
 
 import (
+	"strings"
+
+	//
 	"freepik.com/notifik/api/v1alpha1"
 )
 
@@ -42,7 +45,7 @@ func (m *IntegrationsRegistry) RemoveIntegration(integration *v1alpha1.Integrati
 	integrations := m.registry
 	index := -1
 	for itemIndex, itemObject := range integrations {
-		if itemObject.Name == integration.Name && itemObject.Namespace == integration.Namespace {
+		if GetIntegrationKey(itemObject) == GetIntegrationKey(integration) {
 			index = itemIndex
 			break
 		}
@@ -52,6 +55,20 @@ func (m *IntegrationsRegistry) RemoveIntegration(integration *v1alpha1.Integrati
 	}
 }
 
+// GetIntegration return the integration with the provided key. Keys are built with GetKey
+func (m *IntegrationsRegistry) GetIntegration(key string) (integration *v1alpha1.Integration, exists bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, itemObject := range m.registry {
+		if GetIntegrationKey(itemObject) == key {
+			return itemObject, true
+		}
+	}
+
+	return nil, false
+}
+
 // GetIntegrations return all the integrations
 func (m *IntegrationsRegistry) GetIntegrations() []*v1alpha1.Integration {
 	m.mu.Lock()
@@ -64,3 +81,32 @@ func (m *IntegrationsRegistry) GetIntegrations() []*v1alpha1.Integration {
 
 	return []*v1alpha1.Integration{}
 }
+
+// GetKey return the key of an Integration of the provided kind in the registry.
+// ClusterIntegrations are prefixed with their kind, so they can have the same name as Integrations
+func GetKey(kind, name string) string {
+	if kind == v1alpha1.ClusterIntegrationKind {
+		return kind + "/" + name
+	}
+
+	return name
+}
+
+// GetIntegrationKey return the key of the integration in the registry
+func GetIntegrationKey(integration *v1alpha1.Integration) string {
+	return GetKey(integration.Kind, integration.Name)
+}
+
+// GetReferenceKey return the key of the integration referenced by a message of a Notification
+func GetReferenceKey(reference *v1alpha1.NotificationIntegration) string {
+	return GetKey(reference.Kind, reference.Name)
+}
+
+// ParseKey return the kind and the name of the integration with the provided key
+func ParseKey(key string) (kind, name string) {
+	if name, found := strings.CutPrefix(key, v1alpha1.ClusterIntegrationKind+"/"); found {
+		return v1alpha1.ClusterIntegrationKind, name
+	}
+
+	return v1alpha1.IntegrationKind, key
+}
@@ -17,51 +17,76 @@ limitations under the License.
 package notifications
 
 import (
+	"fmt"
 	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/expression"
+	"freepik.com/notifik/internal/globals"
+	"freepik.com/notifik/internal/template"
+	"freepik.com/notifik/internal/tenancy"
+	"github.com/google/cel-go/cel"
 	"golang.org/x/exp/maps"
 	"reflect"
+	"regexp"
 	"slices"
-	"strings"
 )
 
 func NewNotificationsRegistry() *NotificationsRegistry {
 	return &NotificationsRegistry{
 		registry: make(map[ResourceTypeName][]*v1alpha1.Notification),
+		compiled: make(map[string]*CompiledNotification),
 	}
 }
 
-// AddNotification add a notification of provided type into registry
-func (m *NotificationsRegistry) AddNotification(rt ResourceTypeName, notification *v1alpha1.Notification) {
+// AddNotification add a notification of provided type into registry, restricted to the provided scope.
+// Its CEL expressions, templates and regular expressions are compiled once here, so the notification is not added when any of them is broken.
+// A previous version of the notification is replaced under the same lock, so it is never seen missing nor unrestricted.
+// It is removed when the new version is broken, so a broken notification never delivers anything
+func (m *NotificationsRegistry) AddNotification(rt ResourceTypeName, notification *v1alpha1.Notification,
+	scope *tenancy.Scope) (err error) {
+	compiled, err := compileNotification(notification)
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// temporaryManifest := (*notificationManifest).DeepCopy()
-	//	*notificationList = append(*notificationList, temporaryManifest)
+	m.removeNotification(notification)
+	if err != nil {
+		return err
+	}
+	compiled.Scope = scope
 
 	m.registry[rt] = append(m.registry[rt], notification)
+	m.compiled[getNotificationKey(notification)] = compiled
+
+	return nil
 }
 
-// RemoveNotification delete a notification of provided type
-func (m *NotificationsRegistry) RemoveNotification(rt ResourceTypeName, notification *v1alpha1.Notification) {
+// RemoveNotification delete a notification from the registry, whatever the type it was registered for
+func (m *NotificationsRegistry) RemoveNotification(notification *v1alpha1.Notification) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	notifications := m.registry[rt]
-	index := -1
-	for itemIndex, itemObject := range notifications {
-		if itemObject.Name == notification.Name && itemObject.Namespace == notification.Namespace {
-			index = itemIndex
-			break
+	m.removeNotification(notification)
+}
+
+// removeNotification delete a notification from all the types. The lock must be held by the caller.
+// Lists are copied instead of modified, as they are iterated by the callers of GetNotifications without the lock
+func (m *NotificationsRegistry) removeNotification(notification *v1alpha1.Notification) {
+	for rt, notifications := range m.registry {
+		index := slices.IndexFunc(notifications, func(itemObject *v1alpha1.Notification) bool {
+			return itemObject.Name == notification.Name && itemObject.Namespace == notification.Namespace
+		})
+		if index == -1 {
+			continue
 		}
-	}
-	if index != -1 {
-		m.registry[rt] = append(notifications[:index], notifications[index+1:]...)
-	}
+		m.registry[rt] = slices.Concat(notifications[:index], notifications[index+1:])
 
-	// Delete index from registry when any Notification resource is needing it
-	if len(m.registry[rt]) == 0 {
-		delete(m.registry, rt)
+		// Delete index from registry when any Notification resource is needing it
+		if len(m.registry[rt]) == 0 {
+			delete(m.registry, rt)
+		}
 	}
+
+	delete(m.compiled, getNotificationKey(notification))
 }
 
 // GetNotifications return all the notifications of provided type
@@ -77,6 +102,33 @@ func (m *NotificationsRegistry) GetNotifications(rt ResourceTypeName) []*v1alpha
 	return []*v1alpha1.Notification{}
 }
 
+// GetNotification return the notification with the provided namespace and name
+func (m *NotificationsRegistry) GetNotification(namespace, name string) (notification *v1alpha1.Notification, exists bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, resourceList := range m.registry {
+		for _, itemObject := range resourceList {
+			if itemObject.Namespace == namespace && itemObject.Name == name {
+				return itemObject, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// GetCompiled return the compiled CEL expressions, templates and scope of the notification.
+// Notifications not registered anymore are not found, and their events must be dropped,
+// as nothing restricts what they can see or deliver to
+func (m *NotificationsRegistry) GetCompiled(notification *v1alpha1.Notification) (compiled *CompiledNotification, exists bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	compiled, exists = m.compiled[getNotificationKey(notification)]
+	return compiled, exists
+}
+
 // GetRegisteredResourceTypes returns TODO
 func (m *NotificationsRegistry) GetRegisteredResourceTypes() []ResourceTypeName {
 	m.mu.Lock()
@@ -104,10 +156,7 @@ func (m *NotificationsRegistry) GetRegisteredExtraResourcesTypes() []ResourceTyp
 					continue
 				}
 
-				extraResourceName := strings.Join([]string{
-					extraResource.Group, extraResource.Version, extraResource.Resource,
-					extraResource.Namespace, extraResource.Name,
-				}, "/")
+				extraResourceName := globals.ResourceTypeFromExtraResource(&extraResource).String()
 
 				extraResourceTypes = append(extraResourceTypes, extraResourceName)
 			}
@@ -120,3 +169,80 @@ func (m *NotificationsRegistry) GetRegisteredExtraResourcesTypes() []ResourceTyp
 
 	return extraResourceTypes
 }
+
+// getNotificationKey return the key used to index data of the notification
+func getNotificationKey(notification *v1alpha1.Notification) string {
+	return notification.Namespace + "/" + notification.Name
+}
+
+// compileNotification compiles the CEL expressions, templates and regular expressions of the notification.
+// Selectors of the watched and extra resources are checked too, as informers can not be started with broken ones
+func compileNotification(notification *v1alpha1.Notification) (compiled *CompiledNotification, err error) {
+	compiled = &CompiledNotification{
+		Programs: make(map[string]cel.Program),
+		Regexps:  make(map[string]*regexp.Regexp),
+	}
+
+	err = globals.ResourceTypeFromWatch(&notification.Spec.Watch).ValidateSelectors()
+	if err != nil {
+		return nil, fmt.Errorf("watch: %w", err)
+	}
+	for _, resource := range notification.Spec.ExtraResources {
+		err = globals.ResourceTypeFromExtraResource(&resource).ValidateSelectors()
+		if err != nil {
+			return nil, fmt.Errorf("extra resource '%s': %w", resource.Resource, err)
+		}
+	}
+
+	conditions := slices.Clone(notification.Spec.Conditions)
+	for _, group := range notification.Spec.AnyOf {
+		conditions = append(conditions, group.Conditions...)
+	}
+
+	templateStrings := []string{notification.Spec.Throttling.Fingerprint}
+	if notification.Spec.Grouping != nil {
+		templateStrings = append(templateStrings, notification.Spec.Grouping.GroupBy)
+	}
+	for _, condition := range conditions {
+		if condition.Expression == "" {
+			templateStrings = append(templateStrings, condition.Key)
+
+			if condition.Operator != v1alpha1.NotificationConditionOperatorMatches {
+				continue
+			}
+			if _, found := compiled.Regexps[condition.Value]; found {
+				continue
+			}
+
+			regex, err := regexp.Compile(condition.Value)
+			if err != nil {
+				return nil, fmt.Errorf("condition '%s': invalid regular expression: %w", condition.Name, err)
+			}
+			compiled.Regexps[condition.Value] = regex
+			continue
+		}
+
+		if _, found := compiled.Programs[condition.Expression]; found {
+			continue
+		}
+
+		program, err := expression.Compile(condition.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("condition '%s': %w", condition.Name, err)
+		}
+		compiled.Programs[condition.Expression] = program
+	}
+
+	for _, message := range notification.Spec.GetMessages() {
+		templateStrings = append(templateStrings, message.Data, message.Alertmanager.GeneratorUrl)
+		templateStrings = append(templateStrings, maps.Values(message.Alertmanager.Labels)...)
+		templateStrings = append(templateStrings, maps.Values(message.Alertmanager.Annotations)...)
+	}
+
+	compiled.Templates, err = template.CompileTemplates(templateStrings...)
+	if err != nil {
+		return nil, fmt.Errorf("template is not valid: %w", err)
+	}
+
+	return compiled, nil
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import (
+	"strings"
+	"testing"
+
+	//
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/expression"
+)
+
+// expressionsData simulates the data injected into conditions for a Pod event
+var expressionsData = map[string]interface{}{
+	"eventType": "MODIFIED",
+	"object": map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "api", "namespace": "default"},
+		"status":   map[string]interface{}{"phase": "Failed", "restartCount": int64(5)},
+	},
+}
+
+// newNotification return a Notification watching pods with the provided conditions
+func newNotification(conditions ...v1alpha1.NotificationCondition) *v1alpha1.Notification {
+	return &v1alpha1.Notification{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pods"},
+		Spec: v1alpha1.NotificationSpec{
+			Watch:      v1alpha1.NotificationWatch{Version: "v1", Resource: "pods"},
+			Conditions: conditions,
+		},
+	}
+}
+
+func TestAddNotificationCompilesExpressions(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    string
+	}{
+		{
+			name:       "bool expression",
+			expression: `object.status.phase == "Failed" && object.status.restartCount > 3`,
+		},
+		{
+			name:       "dynamic expression checked on evaluation",
+			expression: `object.status.phase`,
+		},
+		{
+			name:       "syntax error",
+			expression: `object.status.phase == `,
+			wantErr:    "expression can not be compiled",
+		},
+		{
+			name:       "undeclared variable",
+			expression: `pod.status.phase == "Failed"`,
+			wantErr:    "undeclared reference to 'pod'",
+		},
+		{
+			name:       "result is not a bool",
+			expression: `size(object)`,
+			wantErr:    "expression must return a bool, but returns int",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			notification := newNotification(v1alpha1.NotificationCondition{Name: "test", Expression: test.expression})
+
+			registry := NewNotificationsRegistry()
+			err := registry.AddNotification("v1/pods", notification, nil)
+
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				compiled, _ := registry.GetCompiled(notification)
+				if _, found := compiled.Programs[test.expression]; !found {
+					t.Errorf("expected the expression to be compiled")
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("expected error containing %q, got: %v", test.wantErr, err)
+			}
+			if !strings.Contains(err.Error(), "condition 'test'") {
+				t.Errorf("expected the name of the condition in the error, got: %v", err)
+			}
+			if _, exists := registry.GetNotification("default", "pods"); exists {
+				t.Errorf("expected the notification not to be registered")
+			}
+		})
+	}
+}
+
+func TestCompiledExpressionsEvaluate(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantResult bool
+		wantErr    string
+	}{
+		{
+			name:       "met",
+			expression: `object.status.phase == "Failed" && object.status.restartCount > 3`,
+			wantResult: true,
+		},
+		{
+			name:       "not met",
+			expression: `object.status.phase == "Running"`,
+		},
+		{
+			name:       "missing field",
+			expression: `object.status.reason == "OOMKilled"`,
+			wantErr:    "no such key: reason",
+		},
+		{
+			name:       "missing field checked first",
+			expression: `has(object.status.reason) && object.status.reason == "OOMKilled"`,
+		},
+		{
+			name:       "dynamic result that is not a bool",
+			expression: `object.status.phase`,
+			wantErr:    "expression returned string instead of a bool",
+		},
+		{
+			name:       "previous object missing on additions",
+			expression: `previousObject.status.phase != object.status.phase`,
+			wantErr:    "no such key: status",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			notification := newNotification(v1alpha1.NotificationCondition{Name: "test", Expression: test.expression})
+
+			registry := NewNotificationsRegistry()
+			err := registry.AddNotification("v1/pods", notification, nil)
+			if err != nil {
+				t.Fatalf("unexpected error registering the notification: %v", err)
+			}
+			compiled, _ := registry.GetCompiled(notification)
+
+			result, err := expression.Evaluate(compiled.Programs[test.expression], expressionsData)
+			if result != test.wantResult {
+				t.Errorf("expected result %v, got %v", test.wantResult, result)
+			}
+			if test.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+				t.Errorf("expected error containing %q, got: %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAddNotificationMixesExpressionsAndTemplates(t *testing.T) {
+	celCondition := v1alpha1.NotificationCondition{Name: "failed", Expression: `object.status.phase == "Failed"`}
+	templateCondition := v1alpha1.NotificationCondition{Name: "restarted", Key: "{{ .object.status.restartCount }}",
+		Value: "3", Operator: v1alpha1.NotificationConditionOperatorGreaterThan}
+
+	t.Run("both kinds are compiled", func(t *testing.T) {
+		notification := newNotification(celCondition, templateCondition)
+		notification.Spec.AnyOf = []v1alpha1.NotificationConditionGroup{{
+			Conditions: []v1alpha1.NotificationCondition{
+				{Name: "api", Expression: `object.metadata.name.startsWith("api")`},
+				{Name: "default", Key: "{{ .object.metadata.namespace }}", Value: "default"},
+			},
+		}}
+
+		registry := NewNotificationsRegistry()
+		err := registry.AddNotification("v1/pods", notification, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		compiled, _ := registry.GetCompiled(notification)
+
+		for _, expressionString := range []string{celCondition.Expression, `object.metadata.name.startsWith("api")`} {
+			result, err := expression.Evaluate(compiled.Programs[expressionString], expressionsData)
+			if err != nil || !result {
+				t.Errorf("expected expression %q to be met, got %v and error: %v", expressionString, result, err)
+			}
+		}
+		for _, templateString := range []string{templateCondition.Key, "{{ .object.metadata.namespace }}"} {
+			if _, found := compiled.Templates[templateString]; !found {
+				t.Errorf("expected template %q to be compiled", templateString)
+			}
+		}
+
+		// Keys of CEL conditions are not templates, so they are not compiled
+		if len(compiled.Programs) != 2 {
+			t.Errorf("expected 2 programs, got %d", len(compiled.Programs))
+		}
+	})
+
+	t.Run("a broken template rejects the notification", func(t *testing.T) {
+		brokenTemplateCondition := templateCondition
+		brokenTemplateCondition.Key = "{{ .object.status.restartCount "
+
+		registry := NewNotificationsRegistry()
+		err := registry.AddNotification("v1/pods", newNotification(celCondition, brokenTemplateCondition), nil)
+		if err == nil || !strings.Contains(err.Error(), "template is not valid") {
+			t.Errorf("expected a template error, got: %v", err)
+		}
+	})
+
+	t.Run("a broken expression rejects the notification", func(t *testing.T) {
+		brokenCelCondition := celCondition
+		brokenCelCondition.Expression = `object.status.phase ==`
+
+		registry := NewNotificationsRegistry()
+		err := registry.AddNotification("v1/pods", newNotification(brokenCelCondition, templateCondition), nil)
+		if err == nil || !strings.Contains(err.Error(), "condition 'failed'") {
+			t.Errorf("expected an expression error, got: %v", err)
+		}
+	})
+}
@@ -18,6 +18,10 @@ package notifications
 
 import (
 	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/template"
+	"freepik.com/notifik/internal/tenancy"
+	"github.com/google/cel-go/cel"
+	"regexp"
 	"sync"
 )
 
@@ -27,4 +31,20 @@ type ResourceTypeName = string
 type NotificationsRegistry struct {
 	mu       sync.Mutex
 	registry map[ResourceTypeName][]*v1alpha1.Notification
+
+	// compiled stores what is compiled of each Notification, indexed by 'namespace/name'
+	compiled map[string]*CompiledNotification
+}
+
+// CompiledNotification holds the CEL expressions, templates and regular expressions of a Notification,
+// compiled once on registration
+type CompiledNotification struct {
+	Programs  map[string]cel.Program
+	Templates template.Templates
+
+	// Regexps are the regular expressions of conditions using the 'Matches' operator, indexed by their source
+	Regexps map[string]*regexp.Regexp
+
+	// Scope restricts what the Notification can watch, source and deliver to. It is nil when tenancy is disabled
+	Scope *tenancy.Scope
 }
@@ -0,0 +1,270 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package silences
+
+import (
+	"fmt"
+	"time"
+
+	//
+	"golang.org/x/exp/maps"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/cron"
+)
+
+func NewSilencesRegistry() *SilencesRegistry {
+	return &SilencesRegistry{
+		registry: make(map[string]*CompiledSilence),
+		silenced: make(map[string]*SilencedRecord),
+	}
+}
+
+// AddSilence add a silence into registry, replacing the previous version of it.
+// Its selectors and schedule are parsed once here, so the silence is not added when any of them is broken
+func (m *SilencesRegistry) AddSilence(silence *v1alpha1.Silence) (err error) {
+	compiled, err := compileSilence(silence)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.registry[getSilenceKey(silence)] = compiled
+	return nil
+}
+
+// RemoveSilence delete a silence, and the record of the messages it silenced
+func (m *SilencesRegistry) RemoveSilence(silence *v1alpha1.Silence) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.registry, getSilenceKey(silence))
+	delete(m.silenced, getSilenceKey(silence))
+}
+
+// GetSilence return the silence with the provided namespace and name
+func (m *SilencesRegistry) GetSilence(namespace, name string) (silence *CompiledSilence, exists bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	silence, exists = m.registry[namespace+"/"+name]
+	return silence, exists
+}
+
+// GetSilences return all the silences
+func (m *SilencesRegistry) GetSilences() []*CompiledSilence {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return maps.Values(m.registry)
+}
+
+// IsSilenced checks whether the message of the Notification for the object is silenced by any silence.
+// The message is recorded in all the silences matching it
+func (m *SilencesRegistry) IsSilenced(notification *v1alpha1.Notification, object map[string]interface{},
+	now time.Time) (silenced bool) {
+
+	var silenceKeys []string
+	for _, silence := range m.GetSilences() {
+		if !silence.Matches(notification, object, now) {
+			continue
+		}
+
+		silenceKeys = append(silenceKeys, getSilenceKey(silence.Silence))
+	}
+
+	if len(silenceKeys) == 0 {
+		return false
+	}
+
+	m.recordSilenced(silenceKeys, now)
+	return true
+}
+
+// TakeSilenced return the messages silenced by the silence since the last call, and forgets them
+func (m *SilencesRegistry) TakeSilenced(silence *v1alpha1.Silence) (record SilencedRecord) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if silencedRecord, found := m.silenced[getSilenceKey(silence)]; found {
+		record = *silencedRecord
+		delete(m.silenced, getSilenceKey(silence))
+	}
+
+	return record
+}
+
+// RestoreSilenced puts back the messages taken with TakeSilenced when they could not be written,
+// merging them with the ones silenced meanwhile
+func (m *SilencesRegistry) RestoreSilenced(silence *v1alpha1.Silence, record SilencedRecord) {
+	if record.Count == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Silences removed meanwhile have nothing to write their messages into
+	if _, found := m.registry[getSilenceKey(silence)]; !found {
+		return
+	}
+
+	currentRecord, found := m.silenced[getSilenceKey(silence)]
+	if !found {
+		m.silenced[getSilenceKey(silence)] = &record
+		return
+	}
+
+	currentRecord.Count += record.Count
+	if currentRecord.LastTime.Before(record.LastTime) {
+		currentRecord.LastTime = record.LastTime
+	}
+}
+
+// recordSilenced counts a message silenced by the silences, all of them under the same lock
+func (m *SilencesRegistry) recordSilenced(silenceKeys []string, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, silenceKey := range silenceKeys {
+		record, found := m.silenced[silenceKey]
+		if !found {
+			record = &SilencedRecord{}
+			m.silenced[silenceKey] = record
+		}
+
+		record.Count++
+		record.LastTime = now
+	}
+}
+
+// IsActive checks whether the silence is silencing messages at the provided time
+func (c *CompiledSilence) IsActive(now time.Time) bool {
+	spec := &c.Silence.Spec
+
+	if spec.StartsAt != nil && now.Before(spec.StartsAt.Time) {
+		return false
+	}
+	if spec.EndsAt != nil && !now.Before(spec.EndsAt.Time) {
+		return false
+	}
+	if c.schedule == nil {
+		return true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.inWindow(now) {
+		return true
+	}
+
+	// Starts looked for in the same minute are the same, so the window would be the same too
+	minute := now.Truncate(time.Minute)
+	if !minute.Equal(c.windowMinute) {
+		c.windowMinute = minute
+		c.windowStart, c.windowEnd = time.Time{}, time.Time{}
+
+		lastStart, found := c.schedule.Last(now.In(c.location), spec.Duration.Duration)
+		if found {
+			c.windowStart, c.windowEnd = lastStart, lastStart.Add(spec.Duration.Duration)
+		}
+	}
+
+	return c.inWindow(now)
+}
+
+// inWindow checks whether the time is inside the last window started by the schedule. Lock must be held by the caller
+func (c *CompiledSilence) inWindow(now time.Time) bool {
+	return !now.Before(c.windowStart) && now.Before(c.windowEnd)
+}
+
+// Matches checks whether the silence is active, and matches the Notification and the object
+func (c *CompiledSilence) Matches(notification *v1alpha1.Notification, object map[string]interface{},
+	now time.Time) bool {
+	spec := &c.Silence.Spec
+
+	// Silences only apply to the Notifications of their namespace.
+	// ClusterSilences have no namespace, and apply to all the Notifications and ClusterNotifications
+	if c.Silence.Namespace != "" && notification.Namespace != c.Silence.Namespace {
+		return false
+	}
+	if spec.Notification.Name != "" && spec.Notification.Name != notification.Name {
+		return false
+	}
+	if !c.notificationSelector.Matches(labels.Set(notification.Labels)) {
+		return false
+	}
+
+	objectMeta := &unstructured.Unstructured{Object: object}
+	if spec.Object.Namespace != "" && spec.Object.Namespace != objectMeta.GetNamespace() {
+		return false
+	}
+	if !c.objectSelector.Matches(labels.Set(objectMeta.GetLabels())) {
+		return false
+	}
+
+	return c.IsActive(now)
+}
+
+// getSilenceKey return the key used to index data of the silence
+func getSilenceKey(silence *v1alpha1.Silence) string {
+	return silence.Namespace + "/" + silence.Name
+}
+
+// compileSilence parses the selectors and the schedule of the silence
+func compileSilence(silence *v1alpha1.Silence) (compiled *CompiledSilence, err error) {
+	compiled = &CompiledSilence{
+		Silence: silence,
+	}
+
+	compiled.notificationSelector, err = labels.Parse(silence.Spec.Notification.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("notification label selector is not valid: %w", err)
+	}
+
+	compiled.objectSelector, err = labels.Parse(silence.Spec.Object.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("object label selector is not valid: %w", err)
+	}
+
+	if silence.Spec.Schedule != "" {
+		if silence.Spec.Duration == nil || silence.Spec.Duration.Duration <= 0 {
+			return nil, fmt.Errorf("duration is required when schedule is set")
+		}
+
+		compiled.schedule, err = cron.Parse(silence.Spec.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("schedule is not valid: %w", err)
+		}
+
+		compiled.location = time.UTC
+		if silence.Spec.TimeZone != "" {
+			compiled.location, err = time.LoadLocation(silence.Spec.TimeZone)
+			if err != nil {
+				return nil, fmt.Errorf("time zone is not valid: %w", err)
+			}
+		}
+	}
+
+	return compiled, nil
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package silences
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	//
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+)
+
+// newCompiledSilence return the compiled silence with the provided spec, failing the test when it is not valid
+func newCompiledSilence(t *testing.T, spec v1alpha1.SilenceSpec) *CompiledSilence {
+	t.Helper()
+
+	compiled, err := compileSilence(&v1alpha1.Silence{Spec: spec})
+	if err != nil {
+		t.Fatalf("unexpected error compiling the silence: %v", err)
+	}
+	return compiled
+}
+
+// mustParseTime parses a time in RFC3339 format, failing the test when it is not valid
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		t.Fatalf("unexpected error parsing time '%s': %v", value, err)
+	}
+	return parsed
+}
+
+// silenceCheck represents a call to IsActive, and what it must return
+type silenceCheck struct {
+	now        string
+	wantActive bool
+}
+
+func TestCompiledSilenceIsActive(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   v1alpha1.SilenceSpec
+		checks []silenceCheck
+	}{
+		{
+			name: "time windows include their start and exclude their end",
+			spec: v1alpha1.SilenceSpec{
+				StartsAt: &metav1.Time{Time: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)},
+				EndsAt:   &metav1.Time{Time: time.Date(2024, time.June, 2, 0, 0, 0, 0, time.UTC)},
+			},
+			checks: []silenceCheck{
+				{now: "2024-05-31T23:59:59.999Z", wantActive: false},
+				{now: "2024-06-01T00:00:00Z", wantActive: true},
+				{now: "2024-06-01T23:59:59.999Z", wantActive: true},
+				{now: "2024-06-02T00:00:00Z", wantActive: false},
+			},
+		},
+		{
+			name: "scheduled windows include their start and exclude their end",
+			spec: v1alpha1.SilenceSpec{
+				Schedule: "0 2 * * *",
+				Duration: &metav1.Duration{Duration: time.Hour},
+			},
+			checks: []silenceCheck{
+				{now: "2024-06-01T01:59:59.999Z", wantActive: false},
+				{now: "2024-06-01T02:00:00Z", wantActive: true},
+				{now: "2024-06-01T02:59:59.999Z", wantActive: true},
+				{now: "2024-06-01T03:00:00Z", wantActive: false},
+			},
+		},
+		{
+			name: "scheduled windows ending in the middle of a minute end on time",
+			spec: v1alpha1.SilenceSpec{
+				Schedule: "0 2 * * *",
+				Duration: &metav1.Duration{Duration: 90 * time.Second},
+			},
+			checks: []silenceCheck{
+				{now: "2024-06-01T02:01:10Z", wantActive: true},
+				{now: "2024-06-01T02:01:29.999Z", wantActive: true},
+				{now: "2024-06-01T02:01:30Z", wantActive: false},
+				{now: "2024-06-01T02:01:59Z", wantActive: false},
+				{now: "2024-06-01T02:01:20Z", wantActive: true},
+			},
+		},
+		{
+			name: "scheduled windows starting while the silence is inactive are found",
+			spec: v1alpha1.SilenceSpec{
+				Schedule: "*/10 * * * *",
+				Duration: &metav1.Duration{Duration: time.Minute},
+			},
+			checks: []silenceCheck{
+				{now: "2024-06-01T02:01:00Z", wantActive: false},
+				{now: "2024-06-01T02:09:59Z", wantActive: false},
+				{now: "2024-06-01T02:10:00Z", wantActive: true},
+				{now: "2024-06-01T02:11:00Z", wantActive: false},
+			},
+		},
+		{
+			name: "overlapping scheduled windows are joined",
+			spec: v1alpha1.SilenceSpec{
+				Schedule: "0,1 2 * * *",
+				Duration: &metav1.Duration{Duration: 90 * time.Second},
+			},
+			checks: []silenceCheck{
+				{now: "2024-06-01T02:00:00Z", wantActive: true},
+				{now: "2024-06-01T02:01:45Z", wantActive: true},
+				{now: "2024-06-01T02:02:30Z", wantActive: false},
+			},
+		},
+		{
+			name: "schedules are evaluated in their time zone",
+			spec: v1alpha1.SilenceSpec{
+				Schedule: "0 2 * * *",
+				Duration: &metav1.Duration{Duration: time.Hour},
+				TimeZone: "Europe/Madrid",
+			},
+			checks: []silenceCheck{
+				// Madrid is UTC+1 in winter and UTC+2 in summer
+				{now: "2024-01-15T00:59:59Z", wantActive: false},
+				{now: "2024-01-15T01:00:00Z", wantActive: true},
+				{now: "2024-01-15T02:00:00Z", wantActive: false},
+				{now: "2024-07-15T00:00:00Z", wantActive: true},
+				{now: "2024-07-15T01:00:00Z", wantActive: false},
+			},
+		},
+		{
+			name: "scheduled times skipped by daylight saving time do not start the silence",
+			spec: v1alpha1.SilenceSpec{
+				Schedule: "30 2 * * *",
+				Duration: &metav1.Duration{Duration: time.Hour},
+				TimeZone: "Europe/Madrid",
+			},
+			checks: []silenceCheck{
+				// Clocks moved from 2AM to 3AM in Madrid on 2024-03-31
+				{now: "2024-03-30T01:30:00Z", wantActive: true},
+				{now: "2024-03-31T00:30:00Z", wantActive: false},
+				{now: "2024-03-31T01:30:00Z", wantActive: false},
+			},
+		},
+		{
+			name: "schedules only apply inside the time window",
+			spec: v1alpha1.SilenceSpec{
+				StartsAt: &metav1.Time{Time: time.Date(2024, time.June, 1, 2, 30, 0, 0, time.UTC)},
+				Schedule: "0 2 * * *",
+				Duration: &metav1.Duration{Duration: time.Hour},
+			},
+			checks: []silenceCheck{
+				{now: "2024-06-01T02:29:59Z", wantActive: false},
+				{now: "2024-06-01T02:30:00Z", wantActive: true},
+				{now: "2024-06-01T03:00:00Z", wantActive: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiled := newCompiledSilence(t, tt.spec)
+
+			// Checks run in order on the same silence, so the window kept from previous checks is exercised
+			for _, check := range tt.checks {
+				if got := compiled.IsActive(mustParseTime(t, check.now)); got != check.wantActive {
+					t.Errorf("at %s: expected active %t, got %t", check.now, check.wantActive, got)
+				}
+			}
+		})
+	}
+}
+
+func TestAddSilenceRejectsInvalidSchedules(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    v1alpha1.SilenceSpec
+		wantErr string
+	}{
+		{
+			name:    "schedule without duration",
+			spec:    v1alpha1.SilenceSpec{Schedule: "0 2 * * *"},
+			wantErr: "duration is required",
+		},
+		{
+			name: "invalid cron expression",
+			spec: v1alpha1.SilenceSpec{
+				Schedule: "0 2 * *",
+				Duration: &metav1.Duration{Duration: time.Hour},
+			},
+			wantErr: "schedule is not valid",
+		},
+		{
+			name: "unknown time zone",
+			spec: v1alpha1.SilenceSpec{
+				Schedule: "0 2 * * *",
+				Duration: &metav1.Duration{Duration: time.Hour},
+				TimeZone: "Europe/Atlantis",
+			},
+			wantErr: "time zone is not valid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewSilencesRegistry()
+			silence := &v1alpha1.Silence{Spec: tt.spec}
+			silence.Namespace = "default"
+			silence.Name = "silence"
+
+			err := registry.AddSilence(silence)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing '%s', got: %v", tt.wantErr, err)
+			}
+			if _, found := registry.GetSilence("default", "silence"); found {
+				t.Errorf("invalid silences must not be registered")
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package silences
+
+import (
+	"sync"
+	"time"
+
+	//
+	"k8s.io/apimachinery/pkg/labels"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/cron"
+)
+
+type SilencesRegistry struct {
+	mu       sync.Mutex
+	registry map[string]*CompiledSilence
+
+	// silenced stores the messages silenced by each Silence since its status was last updated,
+	// indexed by 'namespace/name'
+	silenced map[string]*SilencedRecord
+}
+
+// CompiledSilence holds the selectors and schedule of a Silence, parsed once on registration
+type CompiledSilence struct {
+	Silence *v1alpha1.Silence
+
+	notificationSelector labels.Selector
+	objectSelector       labels.Selector
+	schedule             *cron.Schedule
+	location             *time.Location
+
+	// The last window [windowStart, windowEnd) started by the schedule is kept, as looking for scheduled starts
+	// is expensive. It is only looked for again when the time is out of it, once per minute at most,
+	// as schedules start on whole minutes
+	mu           sync.Mutex
+	windowMinute time.Time
+	windowStart  time.Time
+	windowEnd    time.Time
+}
+
+// SilencedRecord represents the messages silenced by a Silence
+type SilencedRecord struct {
+	Count    int64
+	LastTime time.Time
+}
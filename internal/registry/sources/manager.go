@@ -17,8 +17,9 @@ limitations under the License.
 package sources
 
 import (
+	"context"
 	"errors"
-	"time"
+	"fmt"
 )
 
 // NewSourcesRegistry TODO
@@ -37,43 +38,69 @@ func (m *SourcesRegistry) RegisterInformer(rt ResourceTypeName) *SourcesInformer
 	m.informers[rt] = &SourcesInformer{
 		Started:    false,
 		StopSignal: make(chan bool),
+		Done:       make(chan struct{}),
 	}
 
 	return m.informers[rt]
 }
 
-// DisableInformer send a signal to the informer to stop
-// and delete it from the registry
-func (m *SourcesRegistry) DisableInformer(rt ResourceTypeName) error {
+// DisableInformer send a signal to the informer to stop, waits until it is done
+// and delete it from the registry. Waiting is cancelled with the context
+func (m *SourcesRegistry) DisableInformer(ctx context.Context, rt ResourceTypeName) error {
 	informer, exists := m.GetInformer(rt)
 	if !exists {
 		return errors.New("extra-resource informer not found")
 	}
 
-	// Send a signal to stop the informer
-	informer.mu.Lock()
-	informer.StopSignal <- true
-	informer.mu.Unlock()
-
-	// Wait for some time to stop the informer
-	stoppedInformer := false
-	for i := 0; i < 10; i++ {
-		if !m.IsStarted(rt) {
-			stoppedInformer = true
-			break
-		}
-		time.Sleep(1 * time.Second)
+	m.stopInformer(informer)
+
+	select {
+	case <-informer.Done:
+	case <-ctx.Done():
+		return fmt.Errorf("impossible to stop the extra-resource informer: %w", ctx.Err())
 	}
-	if !stoppedInformer {
-		return errors.New("impossible to stop the extra-resource informer")
+
+	m.deleteInformer(rt, informer)
+	return nil
+}
+
+// RemoveInformer send a signal to the informer to stop and delete it from the registry, without waiting for it.
+// It is used by the informer itself when it can not keep running
+func (m *SourcesRegistry) RemoveInformer(rt ResourceTypeName, informer *SourcesInformer) {
+	m.stopInformer(informer)
+	m.deleteInformer(rt, informer)
+}
+
+// FinishInformer marks the informer as not started and closes its Done channel.
+// It must be called once by the goroutine of the informer when it finishes
+func (m *SourcesRegistry) FinishInformer(informer *SourcesInformer) {
+	informer.mu.Lock()
+	defer informer.mu.Unlock()
+
+	informer.Started = false
+	close(informer.Done)
+}
+
+// stopInformer send the signal to stop to the informer. It is sent by closing the channel, so it never blocks
+func (m *SourcesRegistry) stopInformer(informer *SourcesInformer) {
+	informer.mu.Lock()
+	defer informer.mu.Unlock()
+
+	if !informer.stopping {
+		close(informer.StopSignal)
+		informer.stopping = true
 	}
+}
 
-	// Delete informer from registry
+// deleteInformer deletes the informer from the registry.
+// It could be registered again meanwhile, so only this one is deleted
+func (m *SourcesRegistry) deleteInformer(rt ResourceTypeName, informer *SourcesInformer) {
 	m.mu.Lock()
-	delete(m.informers, rt)
-	m.mu.Unlock()
+	defer m.mu.Unlock()
 
-	return nil
+	if m.informers[rt] == informer {
+		delete(m.informers, rt)
+	}
 }
 
 // SetStarted updates the 'started' flag of an informer
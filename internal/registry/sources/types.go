@@ -27,6 +27,12 @@ type SourcesInformer struct {
 	Started    bool
 	StopSignal chan bool
 
+	// stopping is set once the stop signal is sent, as it is sent by closing the channel
+	stopping bool
+
+	// Done is closed by the goroutine of the informer once it finishes
+	Done chan struct{}
+
 	// ItemPool represents a pool of stored resources that are being collected by the watcher
 	ItemPool []*map[string]any
 }
@@ -17,9 +17,10 @@ limitations under the License.
 package watchers
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"golang.org/x/exp/maps"
-	"time"
 )
 
 // NewWatchersRegistry TODO
@@ -38,43 +39,69 @@ func (m *WatchersRegistry) RegisterWatcher(rt ResourceTypeName) *ResourceWatcher
 	m.watchers[rt] = &ResourceWatcher{
 		Started:    false,
 		StopSignal: make(chan bool),
+		Done:       make(chan struct{}),
 	}
 
 	return m.watchers[rt]
 }
 
-// DisableWatcher send a signal to the watcher to stop
-// and delete it from the registry
-func (m *WatchersRegistry) DisableWatcher(rt ResourceTypeName) error {
+// DisableWatcher send a signal to the watcher to stop, waits until it is done
+// and delete it from the registry. Waiting is cancelled with the context
+func (m *WatchersRegistry) DisableWatcher(ctx context.Context, rt ResourceTypeName) error {
 	watcher, exists := m.GetWatcher(rt)
 	if !exists {
 		return errors.New("watcher not found")
 	}
 
-	// Send a signal to stop the watcher
-	watcher.mu.Lock()
-	watcher.StopSignal <- true
-	watcher.mu.Unlock()
-
-	// Wait for some time to stop the watcher
-	stoppedWatcher := false
-	for i := 0; i < 10; i++ {
-		if !m.IsStarted(rt) {
-			stoppedWatcher = true
-			break
-		}
-		time.Sleep(1 * time.Second)
+	m.stopWatcher(watcher)
+
+	select {
+	case <-watcher.Done:
+	case <-ctx.Done():
+		return fmt.Errorf("impossible to stop the watcher: %w", ctx.Err())
 	}
-	if !stoppedWatcher {
-		return errors.New("impossible to stop the watcher")
+
+	m.deleteWatcher(rt, watcher)
+	return nil
+}
+
+// RemoveWatcher send a signal to the watcher to stop and delete it from the registry, without waiting for it.
+// It is used by the watcher itself when it can not keep running
+func (m *WatchersRegistry) RemoveWatcher(rt ResourceTypeName, watcher *ResourceWatcher) {
+	m.stopWatcher(watcher)
+	m.deleteWatcher(rt, watcher)
+}
+
+// FinishWatcher marks the watcher as not started and closes its Done channel.
+// It must be called once by the goroutine of the watcher when it finishes
+func (m *WatchersRegistry) FinishWatcher(watcher *ResourceWatcher) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+
+	watcher.Started = false
+	close(watcher.Done)
+}
+
+// stopWatcher send the signal to stop to the watcher. It is sent by closing the channel, so it never blocks
+func (m *WatchersRegistry) stopWatcher(watcher *ResourceWatcher) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+
+	if !watcher.stopping {
+		close(watcher.StopSignal)
+		watcher.stopping = true
 	}
+}
 
-	// Delete watcher from registry
+// deleteWatcher deletes the watcher from the registry.
+// It could be registered again meanwhile, so only this one is deleted
+func (m *WatchersRegistry) deleteWatcher(rt ResourceTypeName, watcher *ResourceWatcher) {
 	m.mu.Lock()
-	delete(m.watchers, rt)
-	m.mu.Unlock()
+	defer m.mu.Unlock()
 
-	return nil
+	if m.watchers[rt] == watcher {
+		delete(m.watchers, rt)
+	}
 }
 
 // GetWatcher return the watcher attached to a resource type
@@ -0,0 +1,94 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watchers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDisableWatcher(t *testing.T) {
+	tests := []struct {
+		name        string
+		finishes    bool
+		wantErr     bool
+		wantDeleted bool
+	}{
+		{
+			name:        "watchers finishing on the stop signal are deleted",
+			finishes:    true,
+			wantDeleted: true,
+		},
+		{
+			name:    "watchers not finishing are kept when the context is done",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewWatchersRegistry()
+			watcher := m.RegisterWatcher("v1/pods")
+
+			go func() {
+				<-watcher.StopSignal
+				if test.finishes {
+					m.FinishWatcher(watcher)
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			err := m.DisableWatcher(ctx, "v1/pods")
+			if (err != nil) != test.wantErr {
+				t.Fatalf("expected error %v, got: %v", test.wantErr, err)
+			}
+			if test.wantErr && !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("expected the error of the context, got: %v", err)
+			}
+
+			if _, exists := m.GetWatcher("v1/pods"); exists == test.wantDeleted {
+				t.Errorf("expected the watcher to be deleted %v", test.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestDisableWatcherKeepsNewerWatchers(t *testing.T) {
+	m := NewWatchersRegistry()
+	watcher := m.RegisterWatcher("v1/pods")
+
+	// The resource type is watched again while the previous watcher stops
+	var newerWatcher *ResourceWatcher
+	go func() {
+		<-watcher.StopSignal
+		newerWatcher = m.RegisterWatcher("v1/pods")
+		m.FinishWatcher(watcher)
+	}()
+
+	err := m.DisableWatcher(context.Background(), "v1/pods")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if registeredWatcher, exists := m.GetWatcher("v1/pods"); !exists || registeredWatcher != newerWatcher {
+		t.Errorf("expected the newer watcher to be kept")
+	}
+}
@@ -26,6 +26,12 @@ type ResourceWatcher struct {
 
 	Started    bool
 	StopSignal chan bool
+
+	// stopping is set once the stop signal is sent, as it is sent by closing the channel
+	stopping bool
+
+	// Done is closed by the goroutine of the watcher once it finishes
+	Done chan struct{}
 }
 
 // WatchersRegistry manage watchers' lifecycle
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"log"
 	"strings"
+	"sync"
 	"text/template"
 
 	"github.com/BurntSushi/toml"
@@ -34,15 +35,52 @@ import (
 // for people who are already comfortable with Helm. Not all the extra functionality was added to keep this simpler.
 // Ref: https://github.com/helm/helm/blob/main/pkg/engine/funcs.go
 
-func EvaluateTemplate(templateString string, data map[string]interface{}) (result string, err error) {
-	templateFunctionsMap := GetFunctionsMap()
+// functionsMap is built once, as building sprig functions is expensive and they never change
+var functionsMap = sync.OnceValue(GetFunctionsMap)
+
+// Templates holds parsed templates indexed by their source, so they are parsed once and executed many times.
+// It is safe to execute them concurrently
+type Templates map[string]*template.Template
+
+// Evaluate executes the template with the given source, parsing it first when it is not cached
+func (t Templates) Evaluate(templateString string, data map[string]interface{}) (result string, err error) {
+	parsedTemplate, cached := t[templateString]
+	if !cached {
+		parsedTemplate, err = CompileTemplate(templateString)
+		if err != nil {
+			return result, err
+		}
+	}
 
-	// Create a Template object from the given string
-	parsedTemplate, err := template.New("main").Funcs(templateFunctionsMap).Parse(templateString)
-	if err != nil {
-		return result, err
+	return ExecuteTemplate(parsedTemplate, data)
+}
+
+// CompileTemplates parses all the templates, returning them indexed by their source
+func CompileTemplates(templateStrings ...string) (templates Templates, err error) {
+	templates = make(Templates, len(templateStrings))
+
+	for _, templateString := range templateStrings {
+		if _, cached := templates[templateString]; cached {
+			continue
+		}
+
+		templates[templateString], err = CompileTemplate(templateString)
+		if err != nil {
+			return nil, err
+		}
 	}
 
+	return templates, nil
+}
+
+// CompileTemplate parses a template, ready to be executed
+func CompileTemplate(templateString string) (parsedTemplate *template.Template, err error) {
+	return template.New("main").Funcs(functionsMap()).Parse(templateString)
+}
+
+// ExecuteTemplate executes a parsed template with the given data
+func ExecuteTemplate(parsedTemplate *template.Template, data map[string]interface{}) (result string, err error) {
+
 	// Create a new buffer to store the templating result
 	buffer := new(bytes.Buffer)
 
@@ -54,6 +92,25 @@ func EvaluateTemplate(templateString string, data map[string]interface{}) (resul
 	return buffer.String(), nil
 }
 
+// EvaluateTemplate parses and executes a template.
+// Prefer Templates when the same template is evaluated several times
+func EvaluateTemplate(templateString string, data map[string]interface{}) (result string, err error) {
+
+	// Create a Template object from the given string
+	parsedTemplate, err := CompileTemplate(templateString)
+	if err != nil {
+		return result, err
+	}
+
+	return ExecuteTemplate(parsedTemplate, data)
+}
+
+// ParseTemplate checks whether the template is syntactically valid, without executing it
+func ParseTemplate(templateString string) (err error) {
+	_, err = CompileTemplate(templateString)
+	return err
+}
+
 // GetFunctionsMap return a map with equivalency between functions for inside templating and real Golang ones
 func GetFunctionsMap() template.FuncMap {
 	f := sprig.TxtFuncMap()
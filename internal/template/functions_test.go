@@ -0,0 +1,94 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"testing"
+	gotemplate "text/template"
+)
+
+const benchmarkTemplate = `{{- $object := .object -}}
+{{- printf "%s/%s was %s: %s" $object.metadata.namespace $object.metadata.name .eventType (toJson $object.status) -}}`
+
+// benchmarkData simulates the data injected into templates for a Pod event
+var benchmarkData = map[string]interface{}{
+	"eventType": "MODIFIED",
+	"object": map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":      "example",
+			"namespace": "default",
+		},
+		"status": map[string]interface{}{
+			"phase": "Running",
+		},
+	},
+}
+
+func TestTemplatesEvaluate(t *testing.T) {
+	templates, err := CompileTemplates(benchmarkTemplate)
+	if err != nil {
+		t.Fatalf("unexpected error compiling the template: %s", err)
+	}
+
+	// Cached and not cached templates must render the same
+	expected, err := EvaluateTemplate(benchmarkTemplate, benchmarkData)
+	if err != nil {
+		t.Fatalf("unexpected error evaluating the template: %s", err)
+	}
+
+	for name, templates := range map[string]Templates{"cached": templates, "not cached": nil} {
+		result, err := templates.Evaluate(benchmarkTemplate, benchmarkData)
+		if err != nil {
+			t.Fatalf("%s: unexpected error evaluating the template: %s", name, err)
+		}
+		if result != expected {
+			t.Errorf("%s: expected '%s', got '%s'", name, expected, result)
+		}
+	}
+}
+
+// BenchmarkEvaluateTemplate builds the functions and parses the template on every evaluation,
+// as done before caching them
+func BenchmarkEvaluateTemplate(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		parsedTemplate, err := gotemplate.New("").Funcs(GetFunctionsMap()).Parse(benchmarkTemplate)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		_, err = ExecuteTemplate(parsedTemplate, benchmarkData)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkTemplatesEvaluate executes the template parsed when the Notification was registered
+func BenchmarkTemplatesEvaluate(b *testing.B) {
+	templates, err := CompileTemplates(benchmarkTemplate)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := templates.Evaluate(benchmarkTemplate, benchmarkData)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
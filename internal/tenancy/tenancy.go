@@ -0,0 +1,143 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenancy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	//
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/globals"
+)
+
+const (
+	// AllowedNamespacesAnnotation is set on a Namespace to let its Notifications watch and source objects
+	// from other namespaces. It is a comma-separated list of namespaces, where '*' means all of them
+	AllowedNamespacesAnnotation = "notifik.freepik.com/allowed-namespaces"
+
+	// AllNamespaces allows any namespace, and cluster-scoped objects
+	AllNamespaces = "*"
+
+	//
+	WatchedNamespaceNotAllowedError    = "watched namespace '%s' is not allowed for Notifications in namespace '%s'"
+	SourcedNamespaceNotAllowedError    = "namespace '%s' of extra resource is not allowed for Notifications in namespace '%s'"
+	IntegrationNotSharedError          = "integration '%s' is not shared with namespace '%s'"
+	NamespaceRetrievalError            = "error getting namespace '%s': %s"
+	clusterWideNamespaceRepresentation = "(all)"
+)
+
+// Scope represents what Notifications of a namespace are allowed to use when tenancy is enabled.
+// A nil Scope allows everything
+type Scope struct {
+	// Namespace is the namespace of the Notifications. It is always allowed
+	Namespace string
+
+	// AllowedNamespaces are the other namespaces allowed by the cluster administrators
+	AllowedNamespaces []string
+}
+
+// NewScope builds the Scope of the Notifications in a namespace, out of the annotations of the Namespace.
+// Namespaces are cluster-scoped, so they can not be modified by the tenants
+func NewScope(ctx context.Context, c client.Client, namespace string) (scope *Scope, err error) {
+	namespaceObj := &corev1.Namespace{}
+	err = c.Get(ctx, client.ObjectKey{Name: namespace}, namespaceObj)
+	if err != nil {
+		return nil, fmt.Errorf(NamespaceRetrievalError, namespace, err.Error())
+	}
+
+	scope = &Scope{
+		Namespace: namespace,
+	}
+
+	for _, allowedNamespace := range strings.Split(namespaceObj.Annotations[AllowedNamespacesAnnotation], ",") {
+		allowedNamespace = strings.TrimSpace(allowedNamespace)
+		if allowedNamespace != "" {
+			scope.AllowedNamespaces = append(scope.AllowedNamespaces, allowedNamespace)
+		}
+	}
+
+	return scope, nil
+}
+
+// AllowsNamespace checks whether objects in the namespace can be watched or sourced.
+// Empty namespace represents cluster-scoped objects, or objects in all namespaces
+func (s *Scope) AllowsNamespace(namespace string) bool {
+	if s == nil || slices.Contains(s.AllowedNamespaces, AllNamespaces) {
+		return true
+	}
+
+	return namespace != "" && (namespace == s.Namespace || slices.Contains(s.AllowedNamespaces, namespace))
+}
+
+// AllowsIntegration checks whether the Integration is shared with the namespace.
+// ClusterIntegrations are shared with all the namespaces
+func (s *Scope) AllowsIntegration(integration *v1alpha1.Integration) bool {
+	if s == nil || integration.Kind == v1alpha1.ClusterIntegrationKind {
+		return true
+	}
+
+	sharedNamespaces := integration.Spec.SharedNamespaces
+	return slices.Contains(sharedNamespaces, AllNamespaces) || slices.Contains(sharedNamespaces, s.Namespace)
+}
+
+// AllowsObject checks whether the object can be watched or sourced, looking at its namespace
+func (s *Scope) AllowsObject(object *map[string]any) bool {
+	if s == nil {
+		return true
+	}
+
+	objectBasicData, err := globals.GetObjectBasicData(object)
+	if err != nil {
+		return false
+	}
+
+	namespace, _ := objectBasicData["namespace"].(string)
+	return s.AllowsNamespace(namespace)
+}
+
+// ValidateNotification checks that the namespaces watched and sourced by the Notification are allowed.
+// Those selected by namespace selectors are filtered when events come, as they change over time
+func (s *Scope) ValidateNotification(notification *v1alpha1.Notification) error {
+	watch := notification.Spec.Watch
+	if watch.NamespaceSelector == "" && !s.AllowsNamespace(watch.Namespace) {
+		return fmt.Errorf(WatchedNamespaceNotAllowedError, getNamespaceRepresentation(watch.Namespace), s.Namespace)
+	}
+
+	for _, extraResource := range notification.Spec.ExtraResources {
+		if extraResource.NamespaceSelector == "" && !s.AllowsNamespace(extraResource.Namespace) {
+			return fmt.Errorf(SourcedNamespaceNotAllowedError,
+				getNamespaceRepresentation(extraResource.Namespace), s.Namespace)
+		}
+	}
+
+	return nil
+}
+
+// getNamespaceRepresentation return a readable name for the namespace in messages
+func getNamespaceRepresentation(namespace string) string {
+	if namespace == "" {
+		return clusterWideNamespaceRepresentation
+	}
+	return namespace
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	//
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/controller"
+	"freepik.com/notifik/internal/globals"
+	integrationsRegistry "freepik.com/notifik/internal/registry/integrations"
+)
+
+const (
+	// DefaultFlushPeriod is used when the configured period is not valid
+	DefaultFlushPeriod = 10 * time.Second
+
+	//
+	trackerContextFinishedMessage = "StatusTracker finished by context"
+	statusUpdateErrorMessage      = "Impossible to update the status of Notification '%s': %s"
+)
+
+func NewStatusTracker(options StatusTrackerOptions, dependencies StatusTrackerDependencies) *StatusTracker {
+	return &StatusTracker{
+		records:      map[types.NamespacedName]*notificationRecord{},
+		readiness:    map[types.NamespacedName]string{},
+		Options:      options,
+		Dependencies: dependencies,
+	}
+}
+
+// RecordTrigger remembers that an object met the conditions of the Notification
+func (t *StatusTracker) RecordTrigger(notification *v1alpha1.Notification, object map[string]interface{}) {
+	if t == nil {
+		return
+	}
+
+	objectData := &unstructured.Unstructured{Object: object}
+	now := metav1.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	record := t.getRecord(notification)
+	record.triggers++
+	record.lastTriggeredTime = &now
+	record.lastTriggeredObject = &v1alpha1.NotificationObjectReference{
+		ApiVersion: objectData.GetAPIVersion(),
+		Kind:       objectData.GetKind(),
+		Namespace:  objectData.GetNamespace(),
+		Name:       objectData.GetName(),
+	}
+}
+
+// RecordFailure remembers that the templates of the Notification failed, or its message could not be delivered
+func (t *StatusTracker) RecordFailure(notification *v1alpha1.Notification, err error) {
+	if t == nil {
+		return
+	}
+
+	now := metav1.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	record := t.getRecord(notification)
+	record.failures++
+	record.lastError = err.Error()
+	record.lastErrorTime = &now
+}
+
+// getRecord return the record of the Notification, creating it when needed. Lock must be held by the caller
+func (t *StatusTracker) getRecord(notification *v1alpha1.Notification) *notificationRecord {
+	key := types.NamespacedName{Namespace: notification.Namespace, Name: notification.Name}
+
+	record, recordFound := t.records[key]
+	if !recordFound {
+		record = &notificationRecord{}
+		t.records[key] = record
+	}
+	return record
+}
+
+// Start writes the status of the Notifications periodically, until the context is done
+func (t *StatusTracker) Start() {
+	logger := log.FromContext(*t.Dependencies.Context)
+
+	flushPeriod := t.Options.FlushPeriod
+	if flushPeriod <= 0 {
+		flushPeriod = DefaultFlushPeriod
+	}
+
+	ticker := time.NewTicker(flushPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-(*t.Dependencies.Context).Done():
+			logger.Info(trackerContextFinishedMessage)
+			return
+		case <-ticker.C:
+			t.flush()
+		}
+	}
+}
+
+// flush writes the records and the readiness of the registered Notifications into their status.
+// Notifications are only updated when something changed since the last time
+func (t *StatusTracker) flush() {
+	ctx := *t.Dependencies.Context
+	logger := log.FromContext(ctx)
+
+	registeredKeys := map[types.NamespacedName]bool{}
+
+	for _, resourceType := range t.Dependencies.NotificationsRegistry.GetRegisteredResourceTypes() {
+		for _, notification := range t.Dependencies.NotificationsRegistry.GetNotifications(resourceType) {
+			key := types.NamespacedName{Namespace: notification.Namespace, Name: notification.Name}
+			registeredKeys[key] = true
+
+			readyCondition := t.getReadyCondition(resourceType, notification)
+
+			t.mu.Lock()
+			record := t.records[key]
+			delete(t.records, key)
+			readinessChanged := t.readiness[key] != readyCondition.Message
+			t.mu.Unlock()
+
+			if record == nil && !readinessChanged {
+				continue
+			}
+
+			err := t.updateStatus(ctx, key, record, readyCondition)
+			if err != nil {
+				logger.Info(fmt.Sprintf(statusUpdateErrorMessage, key.String(), err))
+				t.restoreRecord(key, record)
+				continue
+			}
+
+			t.mu.Lock()
+			t.readiness[key] = readyCondition.Message
+			t.mu.Unlock()
+		}
+	}
+
+	// Forget the Notifications that are not registered anymore
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key := range t.records {
+		if !registeredKeys[key] {
+			delete(t.records, key)
+		}
+	}
+	for key := range t.readiness {
+		if !registeredKeys[key] {
+			delete(t.readiness, key)
+		}
+	}
+}
+
+// restoreRecord puts back a record that could not be written, merging it with what happened meanwhile
+func (t *StatusTracker) restoreRecord(key types.NamespacedName, record *notificationRecord) {
+	if record == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	currentRecord, recordFound := t.records[key]
+	if !recordFound {
+		t.records[key] = record
+		return
+	}
+
+	currentRecord.triggers += record.triggers
+	currentRecord.failures += record.failures
+
+	if currentRecord.lastTriggeredTime == nil {
+		currentRecord.lastTriggeredTime = record.lastTriggeredTime
+		currentRecord.lastTriggeredObject = record.lastTriggeredObject
+	}
+	if currentRecord.lastErrorTime == nil {
+		currentRecord.lastError = record.lastError
+		currentRecord.lastErrorTime = record.lastErrorTime
+	}
+}
+
+// getReadyCondition return the Ready condition of the Notification. It is not ready when any of its Integrations
+// does not exist, or the watcher of its resource type is not started
+func (t *StatusTracker) getReadyCondition(resourceType string, notification *v1alpha1.Notification) metav1.Condition {
+	for _, message := range notification.Spec.GetMessages() {
+		integrationName := integrationsRegistry.GetReferenceKey(&message.Integration)
+
+		if _, integrationFound := t.Dependencies.IntegrationsRegistry.GetIntegration(integrationName); !integrationFound {
+			return controller.NewCondition(controller.ConditionTypeReady, metav1.ConditionFalse,
+				controller.ConditionReasonIntegrationNotFound,
+				fmt.Sprintf(controller.ConditionReasonIntegrationNotFoundMessage, integrationName))
+		}
+	}
+
+	for _, watchedResourceType := range t.getWatchedResourceTypes(resourceType) {
+		if !t.Dependencies.WatchersRegistry.IsStarted(watchedResourceType) {
+			return controller.NewCondition(controller.ConditionTypeReady, metav1.ConditionFalse,
+				controller.ConditionReasonWatcherNotStarted,
+				fmt.Sprintf(controller.ConditionReasonWatcherNotStartedMessage, watchedResourceType))
+		}
+	}
+
+	return controller.NewCondition(controller.ConditionTypeReady, metav1.ConditionTrue,
+		controller.ConditionReasonReady, controller.ConditionReasonReadyMessage)
+}
+
+// getWatchedResourceTypes return the resource types watched for a resource type of Notifications.
+// Those using a namespace selector are watched with a watcher per namespace, so they are found in the registry
+func (t *StatusTracker) getWatchedResourceTypes(resourceType string) []string {
+	parsedResourceType, err := globals.ParseResourceTypeName(resourceType)
+	if err != nil || parsedResourceType.NamespaceSelector == "" {
+		return []string{resourceType}
+	}
+
+	watchedResourceTypes := []string{}
+	for _, registeredResourceType := range t.Dependencies.WatchersRegistry.GetRegisteredResourceTypes() {
+		if globals.UnexpandedResourceTypeName(registeredResourceType) == resourceType {
+			watchedResourceTypes = append(watchedResourceTypes, registeredResourceType)
+		}
+	}
+
+	return watchedResourceTypes
+}
+
+// updateStatus writes the record and the Ready condition into the status of the Notification.
+// Counters are added to the ones already stored, so they survive restarts of the controller
+func (t *StatusTracker) updateStatus(ctx context.Context, key types.NamespacedName,
+	record *notificationRecord, readyCondition metav1.Condition) error {
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// ClusterNotifications are the only ones registered without namespace
+		var notification client.Object
+		var status *v1alpha1.NotificationStatus
+		if key.Namespace == "" {
+			clusterNotification := &v1alpha1.ClusterNotification{}
+			notification, status = clusterNotification, &clusterNotification.Status
+		} else {
+			namespacedNotification := &v1alpha1.Notification{}
+			notification, status = namespacedNotification, &namespacedNotification.Status
+		}
+
+		err := t.Dependencies.Client.Get(ctx, key, notification)
+		if err != nil {
+			return client.IgnoreNotFound(err)
+		}
+
+		controller.UpdateCondition(&status.Conditions, readyCondition)
+
+		if record != nil {
+			status.TriggerCount += record.triggers
+			status.FailureCount += record.failures
+
+			if record.lastTriggeredTime != nil {
+				status.LastTriggeredTime = record.lastTriggeredTime
+				status.LastTriggeredObject = record.lastTriggeredObject
+			}
+			if record.lastErrorTime != nil {
+				status.LastError = record.lastError
+				status.LastErrorTime = record.lastErrorTime
+			}
+		}
+
+		return t.Dependencies.Client.Status().Update(ctx, notification)
+	})
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	//
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	integrationsRegistry "freepik.com/notifik/internal/registry/integrations"
+	notificationsRegistry "freepik.com/notifik/internal/registry/notifications"
+	watchersRegistry "freepik.com/notifik/internal/registry/watchers"
+)
+
+// notificationRecord represents what happened with a Notification since its status was written the last time
+type notificationRecord struct {
+	triggers int64
+	failures int64
+
+	lastTriggeredTime   *metav1.Time
+	lastTriggeredObject *v1alpha1.NotificationObjectReference
+
+	lastError     string
+	lastErrorTime *metav1.Time
+}
+
+type StatusTrackerOptions struct {
+	// Duration to wait between writes of the status of Notifications
+	FlushPeriod time.Duration
+}
+
+type StatusTrackerDependencies struct {
+	Context *context.Context
+	Client  client.Client
+
+	//
+	IntegrationsRegistry  *integrationsRegistry.IntegrationsRegistry
+	NotificationsRegistry *notificationsRegistry.NotificationsRegistry
+	WatchersRegistry      *watchersRegistry.WatchersRegistry
+}
+
+// StatusTracker keeps in memory what happens with Notifications while their objects are processed,
+// and writes it into their status periodically. This way the API is not called for each event
+type StatusTracker struct {
+	mu sync.Mutex
+
+	records map[types.NamespacedName]*notificationRecord
+
+	// readiness stores the message of the last Ready condition written for each Notification,
+	// so it is only written again when it changes
+	readiness map[types.NamespacedName]string
+
+	Options      StatusTrackerOptions
+	Dependencies StatusTrackerDependencies
+}
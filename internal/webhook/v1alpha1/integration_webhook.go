@@ -0,0 +1,139 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	//
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/integrations"
+	"freepik.com/notifik/internal/integrations/slack"
+	notifikWebhook "freepik.com/notifik/internal/integrations/webhook"
+)
+
+const (
+	validatorNotFoundErrorMessage = "validator '%s' not found"
+)
+
+// SetupIntegrationWebhookWithManager registers the webhook for Integration in the manager
+func SetupIntegrationWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&v1alpha1.Integration{}).
+		WithValidator(&IntegrationCustomValidator{}).
+		Complete()
+}
+
+// SetupClusterIntegrationWebhookWithManager registers the webhook for ClusterIntegration in the manager
+func SetupClusterIntegrationWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&v1alpha1.ClusterIntegration{}).
+		WithValidator(&IntegrationCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-notifik-freepik-com-v1alpha1-integration,mutating=false,failurePolicy=fail,sideEffects=None,groups=notifik.freepik.com,resources=integrations,verbs=create;update,versions=v1alpha1,name=vintegration-v1alpha1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-notifik-freepik-com-v1alpha1-clusterintegration,mutating=false,failurePolicy=fail,sideEffects=None,groups=notifik.freepik.com,resources=clusterintegrations,verbs=create;update,versions=v1alpha1,name=vclusterintegration-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// IntegrationCustomValidator rejects Integrations and ClusterIntegrations that their driver can not handle
+type IntegrationCustomValidator struct{}
+
+var _ webhook.CustomValidator = &IntegrationCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator
+func (v *IntegrationCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	integration, err := toIntegration(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.validateIntegration(integration)
+}
+
+// ValidateUpdate implements webhook.CustomValidator
+func (v *IntegrationCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	integration, err := toIntegration(newObj)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.validateIntegration(integration)
+}
+
+// ValidateDelete implements webhook.CustomValidator. Deletions are always allowed
+func (v *IntegrationCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateIntegration checks the Integration against its driver. Integrations using credentials are only
+// partially checked, as their values are not expanded until they are reconciled
+func (v *IntegrationCustomValidator) validateIntegration(integration *v1alpha1.Integration) (admission.Warnings, error) {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	if _, driverFound := integrations.GetDriver(integration.Spec.Type); !driverFound {
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("type"), integration.Spec.Type, integrations.GetDriverTypes()))
+		return nil, apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind(integration.Kind).GroupKind(), integration.Name, allErrs)
+	}
+
+	if webhookValidator := integration.Spec.Webhook.Validator; webhookValidator != "" &&
+		!notifikWebhook.ValidatorExists(webhookValidator) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("webhook", "validator"), webhookValidator,
+			fmt.Sprintf(validatorNotFoundErrorMessage, webhookValidator)))
+	}
+
+	if slackValidator := integration.Spec.Slack.Validator; slackValidator != "" &&
+		!slack.ValidatorExists(slackValidator) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("slack", "validator"), slackValidator,
+			fmt.Sprintf(validatorNotFoundErrorMessage, slackValidator)))
+	}
+
+	if len(allErrs) == 0 && reflect.ValueOf(integration.Spec.Credentials.SecretRef).IsZero() {
+		err := integrations.ValidateIntegration(integration)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath, integration.Spec.Type, err.Error()))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+
+	return nil, apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind(integration.Kind).GroupKind(), integration.Name, allErrs)
+}
+
+// toIntegration return the object as an Integration of its kind, so both kinds are validated the same way
+func toIntegration(obj runtime.Object) (*v1alpha1.Integration, error) {
+	switch integration := obj.(type) {
+	case *v1alpha1.Integration:
+		integration = integration.DeepCopy()
+		integration.Kind = v1alpha1.IntegrationKind
+		return integration, nil
+	case *v1alpha1.ClusterIntegration:
+		return integration.ToIntegration(), nil
+	}
+
+	return nil, fmt.Errorf(unexpectedObjectErrorMessage, "Integration", obj)
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	//
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	//
+	"freepik.com/notifik/api/v1alpha1"
+	"freepik.com/notifik/internal/expression"
+	integrationsRegistry "freepik.com/notifik/internal/registry/integrations"
+	"freepik.com/notifik/internal/template"
+)
+
+const (
+	unexpectedObjectErrorMessage = "expected a %s object but got %T"
+	templateErrorMessage         = "template is not valid: %s"
+	resourceNotFoundErrorMessage = "resource '%s' is not served by the cluster"
+	integrationNotFoundMessage   = "integration '%s' does not exist"
+	regexErrorMessage            = "regular expression is not valid: %s"
+	numberErrorMessage           = "value must be a number or a quantity"
+)
+
+// SetupNotificationWebhookWithManager registers the webhook for Notification in the manager
+func SetupNotificationWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&v1alpha1.Notification{}).
+		WithValidator(&NotificationCustomValidator{
+			Client: mgr.GetClient(),
+			Mapper: mgr.GetRESTMapper(),
+		}).
+		Complete()
+}
+
+// SetupClusterNotificationWebhookWithManager registers the webhook for ClusterNotification in the manager
+func SetupClusterNotificationWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&v1alpha1.ClusterNotification{}).
+		WithValidator(&NotificationCustomValidator{
+			Client: mgr.GetClient(),
+			Mapper: mgr.GetRESTMapper(),
+		}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-notifik-freepik-com-v1alpha1-notification,mutating=false,failurePolicy=fail,sideEffects=None,groups=notifik.freepik.com,resources=notifications,verbs=create;update,versions=v1alpha1,name=vnotification-v1alpha1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-notifik-freepik-com-v1alpha1-clusternotification,mutating=false,failurePolicy=fail,sideEffects=None,groups=notifik.freepik.com,resources=clusternotifications,verbs=create;update,versions=v1alpha1,name=vclusternotification-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// NotificationCustomValidator rejects Notifications and ClusterNotifications with broken templates, or referencing
+// resources not served by the cluster or Integrations that do not exist
+type NotificationCustomValidator struct {
+	Client client.Client
+	Mapper meta.RESTMapper
+}
+
+var _ webhook.CustomValidator = &NotificationCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator
+func (v *NotificationCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	notification, err := toNotification(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.validateNotification(ctx, notification)
+}
+
+// ValidateUpdate implements webhook.CustomValidator
+func (v *NotificationCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	notification, err := toNotification(newObj)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.validateNotification(ctx, notification)
+}
+
+// ValidateDelete implements webhook.CustomValidator. Deletions are always allowed
+func (v *NotificationCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateNotification collects all the problems of the Notification, so they are fixed at once
+func (v *NotificationCustomValidator) validateNotification(ctx context.Context, notification *v1alpha1.Notification) (admission.Warnings, error) {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	// Conditions
+	allErrs = append(allErrs, validateConditions(specPath.Child("conditions"), notification.Spec.Conditions)...)
+	for groupIndex, group := range notification.Spec.AnyOf {
+		allErrs = append(allErrs, validateConditions(specPath.Child("anyOf").Index(groupIndex).Child("conditions"), group.Conditions)...)
+	}
+
+	// Throttling
+	allErrs = append(allErrs, validateTemplate(specPath.Child("throttling").Child("fingerprint"),
+		notification.Spec.Throttling.Fingerprint)...)
+
+	// Grouping
+	if notification.Spec.Grouping != nil {
+		allErrs = append(allErrs, validateTemplate(specPath.Child("grouping").Child("groupBy"),
+			notification.Spec.Grouping.GroupBy)...)
+	}
+
+	// Messages
+	if notification.Spec.Message != nil {
+		allErrs = append(allErrs, v.validateMessage(ctx, specPath.Child("message"), notification.Spec.Message)...)
+	}
+	for messageIndex := range notification.Spec.Messages {
+		allErrs = append(allErrs, v.validateMessage(ctx, specPath.Child("messages").Index(messageIndex),
+			&notification.Spec.Messages[messageIndex])...)
+	}
+
+	// Watched and extra resources
+	watch := notification.Spec.Watch
+	allErrs = append(allErrs, v.validateResource(specPath.Child("watch"), watch.Group, watch.Version, watch.Resource)...)
+	allErrs = append(allErrs, validateSelectors(specPath.Child("watch"),
+		watch.LabelSelector, watch.FieldSelector, watch.NamespaceSelector)...)
+
+	for resourceIndex, resource := range notification.Spec.ExtraResources {
+		allErrs = append(allErrs, v.validateResource(specPath.Child("extraResources").Index(resourceIndex),
+			resource.Group, resource.Version, resource.Resource)...)
+		allErrs = append(allErrs, validateSelectors(specPath.Child("extraResources").Index(resourceIndex),
+			resource.LabelSelector, resource.FieldSelector, resource.NamespaceSelector)...)
+	}
+
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+
+	return nil, apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind(notification.Kind).GroupKind(), notification.Name, allErrs)
+}
+
+// toNotification return the object as a Notification of its kind, so both kinds are validated the same way
+func toNotification(obj runtime.Object) (*v1alpha1.Notification, error) {
+	switch notification := obj.(type) {
+	case *v1alpha1.Notification:
+		notification = notification.DeepCopy()
+		notification.Kind = v1alpha1.NotificationKind
+		return notification, nil
+	case *v1alpha1.ClusterNotification:
+		return notification.ToNotification(), nil
+	}
+
+	return nil, fmt.Errorf(unexpectedObjectErrorMessage, "Notification", obj)
+}
+
+// validateMessage checks the templates of the message, and the existence of its Integration
+func (v *NotificationCustomValidator) validateMessage(ctx context.Context, messagePath *field.Path,
+	message *v1alpha1.NotificationMessage) field.ErrorList {
+	allErrs := validateTemplate(messagePath.Child("data"), message.Data)
+
+	alertmanagerPath := messagePath.Child("alertmanager")
+	for labelKey, labelTemplate := range message.Alertmanager.Labels {
+		allErrs = append(allErrs, validateTemplate(alertmanagerPath.Child("labels").Key(labelKey), labelTemplate)...)
+	}
+	for annotationKey, annotationTemplate := range message.Alertmanager.Annotations {
+		allErrs = append(allErrs, validateTemplate(alertmanagerPath.Child("annotations").Key(annotationKey), annotationTemplate)...)
+	}
+	allErrs = append(allErrs, validateTemplate(alertmanagerPath.Child("generatorUrl"), message.Alertmanager.GeneratorUrl)...)
+
+	// Integration, looked up among the resources of the referenced kind
+	integrationPath := messagePath.Child("integration").Child("name")
+	integrationName := message.Integration.Name
+
+	var integration client.Object = &v1alpha1.Integration{}
+	if message.Integration.Kind == v1alpha1.ClusterIntegrationKind {
+		integration = &v1alpha1.ClusterIntegration{}
+	}
+
+	err := v.Client.Get(ctx, types.NamespacedName{Name: integrationName}, integration)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			allErrs = append(allErrs, field.NotFound(integrationPath,
+				fmt.Sprintf(integrationNotFoundMessage, integrationsRegistry.GetReferenceKey(&message.Integration))))
+		} else {
+			allErrs = append(allErrs, field.InternalError(integrationPath, err))
+		}
+	}
+
+	return allErrs
+}
+
+// validateResource checks whether the cluster serves the resource
+func (v *NotificationCustomValidator) validateResource(fieldPath *field.Path, group, version, resource string) field.ErrorList {
+	gvr := schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
+
+	_, err := v.Mapper.KindFor(gvr)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return field.ErrorList{field.Invalid(fieldPath, gvr.String(), fmt.Sprintf(resourceNotFoundErrorMessage, gvr.String()))}
+		}
+		return field.ErrorList{field.InternalError(fieldPath, err)}
+	}
+
+	return nil
+}
+
+// validateConditions checks the templates of the conditions and the values expected by their operators
+func validateConditions(fieldPath *field.Path, conditions []v1alpha1.NotificationCondition) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for conditionIndex, condition := range conditions {
+		conditionPath := fieldPath.Index(conditionIndex)
+
+		// Expressions replace the key and the operator
+		if condition.Expression != "" {
+			_, err := expression.Compile(condition.Expression)
+			if err != nil {
+				allErrs = append(allErrs, field.Invalid(conditionPath.Child("expression"), condition.Expression, err.Error()))
+			}
+			continue
+		}
+
+		if condition.Key == "" {
+			allErrs = append(allErrs, field.Required(conditionPath.Child("key"), "key or expression is required"))
+			continue
+		}
+		allErrs = append(allErrs, validateTemplate(conditionPath.Child("key"), condition.Key)...)
+
+		switch condition.Operator {
+		case v1alpha1.NotificationConditionOperatorIn, v1alpha1.NotificationConditionOperatorNotIn:
+			if len(condition.Values) == 0 {
+				allErrs = append(allErrs, field.Required(conditionPath.Child("values"),
+					fmt.Sprintf("values are required by operator '%s'", condition.Operator)))
+			}
+
+		case v1alpha1.NotificationConditionOperatorMatches:
+			_, err := regexp.Compile(condition.Value)
+			if err != nil {
+				allErrs = append(allErrs, field.Invalid(conditionPath.Child("value"), condition.Value, fmt.Sprintf(regexErrorMessage, err)))
+			}
+
+		case v1alpha1.NotificationConditionOperatorGreaterThan, v1alpha1.NotificationConditionOperatorLessThan:
+			_, err := strconv.ParseFloat(condition.Value, 64)
+			if err != nil {
+				_, err = resource.ParseQuantity(condition.Value)
+			}
+			if err != nil {
+				allErrs = append(allErrs, field.Invalid(conditionPath.Child("value"), condition.Value, numberErrorMessage))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateSelectors checks whether the label, field and namespace selectors can be parsed
+func validateSelectors(fieldPath *field.Path, labelSelector, fieldSelector, namespaceSelector string) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	_, err := labels.Parse(labelSelector)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("labelSelector"), labelSelector, err.Error()))
+	}
+
+	_, err = fields.ParseSelector(fieldSelector)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("fieldSelector"), fieldSelector, err.Error()))
+	}
+
+	_, err = labels.Parse(namespaceSelector)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("namespaceSelector"), namespaceSelector, err.Error()))
+	}
+
+	return allErrs
+}
+
+// validateTemplate checks whether the template can be parsed
+func validateTemplate(fieldPath *field.Path, templateString string) field.ErrorList {
+	if templateString == "" {
+		return nil
+	}
+
+	err := template.ParseTemplate(templateString)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fieldPath, templateString, fmt.Sprintf(templateErrorMessage, err))}
+	}
+
+	return nil
+}